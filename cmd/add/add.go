@@ -1,8 +1,14 @@
 package add
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/egregors/karten/pkg/store"
@@ -13,15 +19,22 @@ import (
 type WordAdder interface {
 	// AddWord adds a new store.Word into store
 	AddWord(w *store.Word) error
+	// Tags returns all tags already used in the store
+	Tags() ([]string, error)
 }
 
 // MetaProvider is remote meta provider to get some Meta data for store.Word
 type MetaProvider interface {
 	// GetMeta perform request to MetaProvider and get meta as a string
-	// if it'S possible
-	GetMeta(w *store.Word) error
+	// if it'S possible. The request should be canceled with ctx.
+	GetMeta(ctx context.Context, w *store.Word) error
+	// GetCandidates returns all words matching the query (e.g. noun and verb), the best match first
+	GetCandidates(ctx context.Context, query string) ([]*store.Word, error)
 }
 
+// DefaultTimeout is max time of a single MetaProvider lookup by default
+const DefaultTimeout = 10 * time.Second
+
 // Srv is service to add new words
 type Srv struct {
 	Store    WordAdder
@@ -29,20 +42,35 @@ type Srv struct {
 
 	UI *tea.Program
 
+	// Deck is the deck all new words will be added to
+	Deck string
+	// Timeout is max time of a single MetaProvider lookup
+	Timeout time.Duration
+
 	dbg bool
 }
 
-// NewSrv creates a new service to adding new words
-func NewSrv(s WordAdder, p MetaProvider, dbg bool) *Srv {
+// NewSrv creates a new service to adding new words into the deck (could be empty)
+func NewSrv(s WordAdder, p MetaProvider, deck string, timeout time.Duration, dbg bool) *Srv {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
 	srv := &Srv{
 		Store:    s,
 		Provider: p,
+		Deck:     deck,
+		Timeout:  timeout,
 		dbg:      dbg,
 	}
 
+	// completion is optional, so it's fine to start without known tags
+	tags, _ := s.Tags()
+
 	srv.UI = tea.NewProgram(addModel{
 		Mode:      addMode,
 		TextInput: makeTextInput(),
+		Spinner:   makeSpinner(),
+		KnownTags: tags,
 		S:         srv,
 	})
 
@@ -59,6 +87,15 @@ const (
 	addMode    = iota // add word (active origin input)
 	saveMode          // save word (got translation from MetaProvider, or from user in manual Mode)
 	manualMode        // set translation by own hands
+	pickMode          // choose one or several of words matching the query
+)
+
+const (
+	// saveMode form fields
+	fieldTags = iota
+	fieldExample
+	fieldMnemonic
+	fieldNotes
 )
 
 type addModel struct {
@@ -66,12 +103,43 @@ type addModel struct {
 
 	TextInput textinput.Model
 
+	// Fields is the form to edit word before saving, Field is focused one
+	Fields []textinput.Model
+	Field  int
+
 	Mode        int
 	CurrentWord *store.Word
 
+	// Pending are lookups in progress, Done are finished ones waiting for the user
+	Pending []pending
+	Done    []lookup
+	Spinner spinner.Model
+	lastID  int
+
+	// Candidates are words to choose in pickMode, Picked are chosen ones by index
+	Candidates []*store.Word
+	Picked     map[int]bool
+	Cursor     int
+
+	// KnownTags are tags from the store used for completion
+	KnownTags []string
+
 	CurrErr error
 }
 
+// pending is a MetaProvider lookup in progress
+type pending struct {
+	id     int
+	origin string
+	cancel context.CancelFunc
+}
+
+// lookupMsg is result of the pending lookup
+type lookupMsg struct {
+	id int
+	lookup
+}
+
 func (m addModel) GetCurrErr() string {
 	if m.CurrErr != nil {
 		return m.CurrErr.Error()
@@ -85,67 +153,248 @@ func (m addModel) Init() tea.Cmd {
 
 func (m addModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case lookupMsg:
+		m.finishLookup(msg)
+		return m, cmd
+
+	case spinner.TickMsg:
+		// spinner stops when there is nothing to wait for
+		if len(m.Pending) == 0 {
+			return m, cmd
+		}
+		m.Spinner, cmd = m.Spinner.Update(msg)
+		return m, cmd
+	}
+
 	if msg, ok := msg.(tea.KeyMsg); ok {
+		if m.Mode == pickMode && msg.Type != tea.KeyCtrlC {
+			return m.updatePick(msg)
+		}
+
 		switch msg.Type {
 		case tea.KeyCtrlC:
+			m.cancelLookups()
 			return m, tea.Quit
 
+		case tea.KeyTab:
+			if m.Mode == saveMode {
+				m.completeTag()
+				return m, cmd
+			}
+
+		case tea.KeyUp, tea.KeyDown:
+			if m.Mode == saveMode {
+				m.Fields[m.Field].Blur()
+				if msg.Type == tea.KeyUp {
+					m.Field = (m.Field + len(m.Fields) - 1) % len(m.Fields)
+				} else {
+					m.Field = (m.Field + 1) % len(m.Fields)
+				}
+				m.Fields[m.Field].Focus()
+				return m, cmd
+			}
+
 		case tea.KeyEsc:
-			if m.Mode == manualMode {
+			switch m.Mode {
+			case addMode:
+				m.cancelLookups()
+				return m, cmd
+			case manualMode:
 				m.Mode = addMode
 				m.updateTextInput()
+				m.openNext()
 				return m, cmd
 			}
 
 		case tea.KeyEnter:
 			switch m.Mode {
 			case addMode:
-				if m.TextInput.Value() == "" {
-					break
-				}
-
-				m.CurrentWord = store.NewWord(m.TextInput.Value())
-				err := m.S.Provider.GetMeta(m.CurrentWord)
-
-				if err != nil {
-					m.CurrErr = err
-					m.Mode = manualMode
-					m.updateTextInput()
+				origin := strings.TrimSpace(m.TextInput.Value())
+				if origin == "" {
+					m.openNext()
 					return m, cmd
 				}
 
-				m.Mode = saveMode
-				m.updateTextInput()
-				return m, cmd
+				m.TextInput.Reset()
+				return m, m.startLookup(origin)
 
 			case manualMode:
-				m.CurrentWord.Translation = m.TextInput.Value()
+				m.CurrentWord.SetTranslation(m.TextInput.Value())
 				m.Mode = saveMode
 				m.updateTextInput()
 				return m, cmd
 
 			case saveMode:
+				tags := store.ParseTags(m.Fields[fieldTags].Value())
+				m.CurrentWord.AddTags(tags...)
+				m.CurrentWord.Deck = m.S.Deck
+				m.CurrentWord.Example = strings.TrimSpace(m.Fields[fieldExample].Value())
+				m.CurrentWord.Mnemonic = strings.TrimSpace(m.Fields[fieldMnemonic].Value())
+				m.CurrentWord.Notes = strings.TrimSpace(m.Fields[fieldNotes].Value())
 				err := m.S.Store.AddWord(m.CurrentWord)
 				if err != nil {
 					// todo: handle error in proper way
 					m.CurrErr = err
 					return m, cmd
 				}
+				m.rememberTags(tags)
 				m.Mode = addMode
 				m.updateTextInput()
+				m.openNext()
 				return m, cmd
 			}
 		}
 	}
 
+	if m.Mode == saveMode {
+		m.Fields[m.Field], cmd = m.Fields[m.Field].Update(msg)
+		return m, cmd
+	}
+
 	m.TextInput, cmd = m.TextInput.Update(msg)
 	return m, cmd
 }
 
+// startLookup runs MetaProvider lookup in background, the user could add next words meanwhile
+func (m *addModel) startLookup(origin string) tea.Cmd {
+	m.lastID++
+	id := m.lastID
+	ctx, cancel := context.WithTimeout(context.Background(), m.S.Timeout)
+	m.Pending = append(m.Pending, pending{id: id, origin: origin, cancel: cancel})
+
+	lookupCmd := func() tea.Msg {
+		defer cancel()
+		ws, err := m.S.Provider.GetCandidates(ctx, origin)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("%s: lookup timed out after %s", origin, m.S.Timeout)
+		}
+
+		l := lookup{query: origin, word: store.NewWord(origin), err: err}
+		if err == nil && len(ws) > 0 {
+			l.word = ws[0]
+		}
+		if err == nil && len(ws) > 1 {
+			l.candidates = ws
+		}
+		return lookupMsg{id: id, lookup: l}
+	}
+
+	if len(m.Pending) == 1 {
+		return tea.Batch(lookupCmd, m.Spinner.Tick)
+	}
+	return lookupCmd
+}
+
+// finishLookup moves the lookup to done ones, results of canceled lookups are dropped
+func (m *addModel) finishLookup(msg lookupMsg) {
+	for i, p := range m.Pending {
+		if p.id != msg.id {
+			continue
+		}
+		m.Pending = append(m.Pending[:i], m.Pending[i+1:]...)
+		m.Done = append(m.Done, msg.lookup)
+		// don't interrupt the user typing the next word
+		if m.Mode == addMode && m.TextInput.Value() == "" {
+			m.openNext()
+		}
+		return
+	}
+}
+
+// openNext opens the first done lookup: the form to save the word, or manual translation if it's failed
+func (m *addModel) openNext() {
+	if len(m.Done) == 0 {
+		return
+	}
+	l := m.Done[0]
+	m.Done = m.Done[1:]
+
+	m.CurrentWord = l.word
+	m.CurrErr = l.err
+	switch {
+	case l.err != nil:
+		m.CurrentWord = store.NewWord(l.query)
+		m.Mode = manualMode
+	case len(l.candidates) > 1:
+		m.CurrentWord = store.NewWord(l.query)
+		m.Candidates, m.Picked, m.Cursor = l.candidates, map[int]bool{}, 0
+		m.Mode = pickMode
+	default:
+		m.Mode = saveMode
+	}
+	m.updateTextInput()
+}
+
+// updatePick handles pickMode: space picks several words, enter saves picked ones or the one under cursor
+func (m addModel) updatePick(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// space comes as a rune
+	switch msg.String() {
+	case "up":
+		m.Cursor = (m.Cursor + len(m.Candidates) - 1) % len(m.Candidates)
+	case "down":
+		m.Cursor = (m.Cursor + 1) % len(m.Candidates)
+	case " ":
+		m.Picked[m.Cursor] = !m.Picked[m.Cursor]
+	case "esc":
+		m.Mode = addMode
+		m.updateTextInput()
+		m.openNext()
+	case "enter":
+		var picked []*store.Word
+		for i, w := range m.Candidates {
+			if m.Picked[i] {
+				picked = append(picked, w)
+			}
+		}
+		if len(picked) == 0 {
+			picked = []*store.Word{m.Candidates[m.Cursor]}
+		}
+		picked = mergeSameOrigin(picked)
+
+		// the rest of picked words are saved one by one after the first one
+		rest := make([]lookup, 0, len(picked)-1+len(m.Done))
+		for _, w := range picked[1:] {
+			rest = append(rest, lookup{query: w.Origin, word: w})
+		}
+		m.Done = append(rest, m.Done...)
+
+		m.CurrentWord, m.Candidates = picked[0], nil
+		m.Mode = saveMode
+		m.updateTextInput()
+	}
+	return m, nil
+}
+
+// mergeSameOrigin merges words of the same origin (e.g. "die Bank" a bench and a bank) into the first of them,
+// as the store keeps one word per origin. Case matters, "essen" and "Essen" are different words.
+func mergeSameOrigin(ws []*store.Word) []*store.Word {
+	var res []*store.Word
+	byOrigin := map[string]*store.Word{}
+	for _, w := range ws {
+		first, ok := byOrigin[w.Origin]
+		if !ok {
+			byOrigin[w.Origin] = w
+			res = append(res, w)
+			continue
+		}
+		first.AddMeanings(w)
+	}
+	return res
+}
+
+func (m *addModel) cancelLookups() {
+	for _, p := range m.Pending {
+		p.cancel()
+	}
+	m.Pending = nil
+}
+
 func (m addModel) View() string {
 	frame := []string{
 		m.titleWidget(),
 		m.inputWidget(),
+		m.lookupsWidget(),
 		m.helpWidget(),
 	}
 
@@ -169,17 +418,55 @@ func (m addModel) inputWidget() string {
 		s = m.TextInput.View()
 	case manualMode:
 		s = m.CurrentWord.Origin + " –" + m.TextInput.View()
-	case saveMode:
-		origin := m.CurrentWord.Origin
-		tr := m.CurrentWord.Translation
-		if tr == "" {
-			tr = m.TextInput.View()
+	case pickMode:
+		s = fmt.Sprintf("%q has several meanings:\n", m.CurrentWord.Origin)
+		for i, w := range m.Candidates {
+			cursor, mark := "  ", "[ ]"
+			if i == m.Cursor {
+				cursor = "> "
+			}
+			if m.Picked[i] {
+				mark = "[x]"
+			}
+			s += fmt.Sprintf("\n%s%s %s – %s", cursor, mark, w.Origin, w.Translation())
+			if forms := preview(w); forms != "" {
+				s += "\n      " + forms
+			}
 		}
-		s = origin + " – " + tr
+	case saveMode:
+		s = m.CurrentWord.Origin + " – " + m.CurrentWord.Translation()
 
 		if m.CurrentWord.Meta != "" {
 			s += "\n\n" + m.CurrentWord.Meta
 		}
+
+		s += "\n"
+		for i, f := range m.Fields {
+			s += "\n" + f.View()
+			if i == fieldTags {
+				if cs := m.tagCandidates(); len(cs) > 0 {
+					s += "\n  " + strings.Join(cs, " ")
+				}
+			}
+		}
+	}
+	return s
+}
+
+func (m addModel) lookupsWidget() string {
+	var s string
+	if m.Mode == manualMode && m.CurrErr != nil {
+		s += "\n" + m.CurrErr.Error() + "\n"
+	}
+	if len(m.Pending) > 0 {
+		origins := make([]string, len(m.Pending))
+		for i, p := range m.Pending {
+			origins[i] = p.origin
+		}
+		s += "\n" + m.Spinner.View() + " looking up: " + strings.Join(origins, ", ")
+	}
+	if len(m.Done) > 0 {
+		s += fmt.Sprintf("\n%d more looked up, press enter on empty input to see them", len(m.Done))
 	}
 	return s
 }
@@ -188,26 +475,116 @@ func (m addModel) helpWidget() string {
 	msg := "\nctrl+c: quit • enter: "
 	switch m.Mode {
 	case addMode:
-		msg += "get translation"
+		msg += "get translation • esc: cancel lookups"
 	case manualMode:
 		msg += "set translation • esc: cancel  f"
 	case saveMode:
-		msg += "save"
+		msg += "save • up/down: switch field • tab: complete tag"
+	case pickMode:
+		msg += "save • up/down: choose • space: pick several • esc: skip"
 	}
 	return msg
 }
 
+// preview returns the first line of word forms from its Meta
+func preview(w *store.Word) string {
+	lines := strings.Split(w.Meta, "\n")
+	if len(lines) < 2 {
+		return ""
+	}
+	return lines[1]
+}
+
 func (m *addModel) updateTextInput() {
 	switch m.Mode {
 	case manualMode:
 		m.TextInput.Reset()
-		m.TextInput.Placeholder = "Translation..."
-	case addMode, saveMode:
+		m.TextInput.Placeholder = "Translation... (go, walk; work)"
+	case saveMode:
+		m.TextInput.Reset()
+		m.Fields = makeFields()
+		m.Field = fieldTags
+	case addMode:
 		m.TextInput.Reset()
 		m.TextInput.Placeholder = "New word..."
 	}
 }
 
+// lastTag returns the tag user is typing right now
+func (m addModel) lastTag() string {
+	if m.Field != fieldTags {
+		return ""
+	}
+	v := m.Fields[fieldTags].Value()
+	if v == "" || strings.HasSuffix(v, " ") || strings.HasSuffix(v, ",") {
+		return ""
+	}
+	tags := store.ParseTags(v)
+	return tags[len(tags)-1]
+}
+
+// tagCandidates returns known tags starting with the tag user is typing right now
+func (m addModel) tagCandidates() []string {
+	prefix := m.lastTag()
+	if prefix == "" {
+		return nil
+	}
+
+	var cs []string
+	for _, t := range m.KnownTags {
+		if strings.HasPrefix(t, prefix) && t != prefix {
+			cs = append(cs, t)
+		}
+	}
+	return cs
+}
+
+// completeTag completes the tag user is typing up to the longest common prefix of candidates
+func (m *addModel) completeTag() {
+	cs := m.tagCandidates()
+	if len(cs) == 0 {
+		return
+	}
+
+	common := []rune(cs[0])
+	for _, c := range cs[1:] {
+		for !strings.HasPrefix(c, string(common)) {
+			common = common[:len(common)-1]
+		}
+	}
+
+	v := m.Fields[fieldTags].Value()
+	v = strings.TrimSuffix(v, m.lastTag()) + string(common)
+	if len(cs) == 1 {
+		v += " "
+	}
+	m.Fields[fieldTags].SetValue(v)
+	m.Fields[fieldTags].CursorEnd()
+}
+
+// rememberTags adds just used tags to completion candidates
+func (m *addModel) rememberTags(tags []string) {
+	for _, t := range tags {
+		known := false
+		for _, k := range m.KnownTags {
+			if k == t {
+				known = true
+				break
+			}
+		}
+		if !known {
+			m.KnownTags = append(m.KnownTags, t)
+		}
+	}
+	sort.Strings(m.KnownTags)
+}
+
+func makeSpinner() spinner.Model {
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+	return sp
+}
+
 func makeTextInput() textinput.Model {
 	ti := textinput.New()
 	ti.Placeholder = "New word..."
@@ -216,3 +593,24 @@ func makeTextInput() textinput.Model {
 	ti.Width = 20
 	return ti
 }
+
+// makeFields makes the form to edit word before saving
+func makeFields() []textinput.Model {
+	placeholders := []string{
+		fieldTags:     "Tags...",
+		fieldExample:  "Example sentence...",
+		fieldMnemonic: "Mnemonic...",
+		fieldNotes:    "Notes...",
+	}
+
+	fs := make([]textinput.Model, len(placeholders))
+	for i, p := range placeholders {
+		fs[i] = textinput.New()
+		fs[i].Placeholder = p
+		fs[i].CharLimit = 512
+		fs[i].Width = 60
+	}
+	fs[fieldTags].Focus()
+
+	return fs
+}
@@ -0,0 +1,40 @@
+package add
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+func TestMergeSameOrigin(t *testing.T) {
+	word := func(origin, translation string) *store.Word {
+		w := store.NewWord(origin)
+		w.SetTranslation(translation)
+		w.Translations = map[string][]store.Sense{"en": w.Senses}
+		w.Meta = origin + "\n" + translation
+		return w
+	}
+	bench, bank := word("die Bank", "bench"), word("die Bank", "bank")
+	food, eat := word("Essen", "food"), word("essen", "to eat")
+
+	got := mergeSameOrigin([]*store.Word{bench, food, bank, eat})
+	var origins []string
+	for _, w := range got {
+		origins = append(origins, w.Origin)
+	}
+	if strings.Join(origins, ",") != "die Bank,Essen,essen" {
+		t.Fatalf("got words %v", origins)
+	}
+
+	b := got[0]
+	if b.Translation() != "bench; bank" || b.TranslationIn("en") != "bench; bank" {
+		t.Errorf("got senses %q, en %q", b.Translation(), b.TranslationIn("en"))
+	}
+	if b.Meta != "die Bank\nbench\nbank" {
+		t.Errorf("got meta %q", b.Meta)
+	}
+	if got[1].Translation() != "food" || got[2].Translation() != "to eat" {
+		t.Errorf("words of different origins are merged: %v", got)
+	}
+}
@@ -0,0 +1,233 @@
+package add
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+// DefaultWorkers is number of concurrent MetaProvider lookups by default
+const DefaultWorkers = 4
+
+// WordsAdder is store able to add a bunch of words
+type WordsAdder interface {
+	// AddWords adds new words, skipping already existing ones
+	AddWords(ws ...*store.Word) (int, error)
+}
+
+// BatchOpts are words to add without UI and their settings
+type BatchOpts struct {
+	// Words are looked up by the MetaProvider, "word=translation" ones are added as is.
+	// Only the best match of a word with several meanings is added, others are reported.
+	Words []string
+	// Manual are "word=translation" words for ones the MetaProvider doesn't know
+	Manual []string
+	// Path is file with a word per line (in any of forms above), "-" for stdin, empty for none
+	Path string
+
+	Deck string
+	Tags []string
+
+	// Workers is max number of concurrent MetaProvider lookups
+	Workers int
+	// Timeout is max time of a single MetaProvider lookup
+	Timeout time.Duration
+}
+
+// BatchSrv is service to add words without UI, e.g. from scripts
+type BatchSrv struct {
+	Store    WordsAdder
+	Provider MetaProvider
+
+	BatchOpts
+
+	In  io.Reader
+	Out io.Writer
+}
+
+// NewBatchSrv creates a new service to add words without UI
+func NewBatchSrv(s WordsAdder, p MetaProvider, opts BatchOpts, in io.Reader, out io.Writer) *BatchSrv {
+	if opts.Workers < 1 {
+		opts.Workers = DefaultWorkers
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	return &BatchSrv{
+		Store:     s,
+		Provider:  p,
+		BatchOpts: opts,
+		In:        in,
+		Out:       out,
+	}
+}
+
+// lookup is result of a single word lookup
+type lookup struct {
+	query string
+	word  *store.Word
+	err   error
+	// candidates are all words matching the query, if there are several of them
+	candidates []*store.Word
+}
+
+// Run looks up all the words and adds found ones. Failed words are reported, and an error is
+// returned if there are any.
+func (srv *BatchSrv) Run() error {
+	queries, err := srv.queries()
+	if err != nil {
+		return err
+	}
+	if len(queries) == 0 {
+		return errors.New("no words to add")
+	}
+
+	res := srv.lookupAll(queries)
+
+	var (
+		ws     []*store.Word
+		failed []lookup
+	)
+	for _, r := range res {
+		if r.err != nil {
+			failed = append(failed, r)
+			continue
+		}
+		if srv.Deck != "" {
+			r.word.Deck = srv.Deck
+		}
+		r.word.AddTags(srv.Tags...)
+		ws = append(ws, r.word)
+	}
+
+	n, err := srv.Store.AddWords(ws...)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range res {
+		if len(r.candidates) > 1 {
+			srv.printf("%s: %d meanings found, added %s – %s; pick others by `karten add` without arguments\n",
+				r.query, len(r.candidates), r.word.Origin, r.word.Translation())
+		}
+	}
+	for _, f := range failed {
+		srv.printf("failed: %s: %s\n", f.query, f.err)
+	}
+	srv.printf("added %d words, %d skipped as already existing, %d failed\n", n, len(ws)-n, len(failed))
+
+	if len(failed) > 0 {
+		srv.printf("add failed words by hand: --manual \"word=translation\"\n")
+		return fmt.Errorf("%d words failed", len(failed))
+	}
+	return nil
+}
+
+// queries returns all words from args and the file, empty lines and #comments are skipped
+func (srv *BatchSrv) queries() ([]string, error) {
+	var qs []string
+	for _, w := range srv.Words {
+		if w = strings.TrimSpace(w); w != "" {
+			qs = append(qs, w)
+		}
+	}
+	for _, w := range srv.Manual {
+		if !strings.Contains(w, "=") {
+			return nil, fmt.Errorf(`bad manual word %q, want "word=translation"`, w)
+		}
+		qs = append(qs, w)
+	}
+
+	if srv.Path == "" {
+		return qs, nil
+	}
+
+	r := srv.In
+	if srv.Path != "-" {
+		f, err := os.Open(filepath.Clean(srv.Path))
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		qs = append(qs, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("can't read %s: %w", srv.Path, err)
+	}
+
+	return qs, nil
+}
+
+// lookupAll makes words for all the queries, keeping the order. Up to Workers lookups run at once.
+func (srv *BatchSrv) lookupAll(queries []string) []lookup {
+	res := make([]lookup, len(queries))
+	sem := make(chan struct{}, srv.Workers)
+
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		res[i].query = q
+
+		if origin, translation, ok := strings.Cut(q, "="); ok {
+			res[i].word, res[i].err = manualWord(origin, translation)
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(r *lookup) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			ctx, cancel := context.WithTimeout(context.Background(), srv.Timeout)
+			defer cancel()
+			ws, err := srv.Provider.GetCandidates(ctx, r.query)
+			r.word, r.err = store.NewWord(r.query), err
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				r.err = fmt.Errorf("lookup timed out after %s", srv.Timeout)
+			}
+			if r.err == nil && len(ws) > 0 {
+				r.word = ws[0]
+			}
+			if r.err == nil && len(ws) > 1 {
+				r.candidates = ws
+			}
+		}(&res[i])
+	}
+	wg.Wait()
+
+	return res
+}
+
+// manualWord makes a word from "word=translation" form
+func manualWord(origin, translation string) (*store.Word, error) {
+	origin, translation = strings.TrimSpace(origin), strings.TrimSpace(translation)
+	if origin == "" || translation == "" {
+		return nil, errors.New(`want "word=translation"`)
+	}
+	w := store.NewWord(origin)
+	w.SetTranslation(translation)
+	return w, nil
+}
+
+func (srv *BatchSrv) printf(format string, a ...any) {
+	_, _ = fmt.Fprintf(srv.Out, format, a...)
+}
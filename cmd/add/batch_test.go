@@ -0,0 +1,81 @@
+package add
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+// dict is MetaProvider with translations of words, several meanings are separated by "|"
+type dict map[string]string
+
+func (d dict) GetMeta(ctx context.Context, w *store.Word) error {
+	ws, err := d.GetCandidates(ctx, w.Origin)
+	if err != nil {
+		return err
+	}
+	*w = *ws[0]
+	return nil
+}
+
+func (d dict) GetCandidates(_ context.Context, query string) ([]*store.Word, error) {
+	t, ok := d[query]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	var ws []*store.Word
+	for _, m := range strings.Split(t, "|") {
+		w := store.NewWord(query)
+		w.SetTranslation(m)
+		ws = append(ws, w)
+	}
+	return ws, nil
+}
+
+type memStore struct {
+	words store.Words
+}
+
+func (m *memStore) AddWords(ws ...*store.Word) (int, error) {
+	m.words = append(m.words, ws...)
+	return len(ws), nil
+}
+
+func TestBatchSrv_Run(t *testing.T) {
+	s := &memStore{}
+	var out strings.Builder
+	opts := BatchOpts{Words: []string{"Haus", "Bank"}, Manual: []string{"Feierabend=end of the working day"}, Tags: []string{"a1"}}
+	err := NewBatchSrv(s, dict{"Haus": "house", "Bank": "bench|bank"}, opts, nil, &out).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, w := range s.words {
+		got = append(got, w.Origin+"="+w.Translation()+" #"+strings.Join(w.Tags, ","))
+	}
+	want := "Haus=house #a1; Bank=bench #a1; Feierabend=end of the working day #a1"
+	if strings.Join(got, "; ") != want {
+		t.Errorf("got words %q, want %q", strings.Join(got, "; "), want)
+	}
+
+	wantOut := "Bank: 2 meanings found, added Bank – bench; pick others by `karten add` without arguments\n" +
+		"added 3 words, 0 skipped as already existing, 0 failed\n"
+	if out.String() != wantOut {
+		t.Errorf("got output:\n%s\nwant:\n%s", out.String(), wantOut)
+	}
+}
+
+func TestBatchSrv_RunFailed(t *testing.T) {
+	s := &memStore{}
+	var out strings.Builder
+	err := NewBatchSrv(s, dict{"Haus": "house"}, BatchOpts{Words: []string{"Haus", "xyz"}}, nil, &out).Run()
+	if err == nil || err.Error() != "1 words failed" {
+		t.Errorf("got %v", err)
+	}
+	if len(s.words) != 1 || !strings.Contains(out.String(), "failed: xyz: ") {
+		t.Errorf("got words %v, output:\n%s", s.words, out.String())
+	}
+}
@@ -0,0 +1,399 @@
+package browse
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/egregors/karten/pkg/store"
+	"github.com/egregors/karten/pkg/widgets"
+	"github.com/muesli/termenv"
+)
+
+var (
+	color         = termenv.EnvColorProfile().Color
+	selectedStyle = termenv.Style{}.Foreground(color("150")).Styled
+	helpStyle     = termenv.Style{}.Foreground(color("241")).Styled
+	errStyle      = termenv.Style{}.Foreground(color("69")).Styled
+)
+
+// WordStore is store able to find, change and delete words
+type WordStore interface {
+	// Find returns all words matching the filter
+	Find(f store.Filter) (store.Words, error)
+	// Save commit changed store.Word in the store
+	Save(w *store.Word) error
+	// Delete removes store.Word by origin
+	Delete(origin string) error
+}
+
+// Srv is service to browse, search and edit all words
+type Srv struct {
+	Store WordStore
+	// Languages are preferred languages of translations
+	Languages []string
+
+	UI *tea.Program
+
+	dbg bool
+}
+
+// NewSrv creates a new service to browse words matching the filter, in the first of languages they have
+func NewSrv(s WordStore, f store.Filter, langs []string, dbg bool) (*Srv, error) {
+	srv := &Srv{
+		Store:     s,
+		Languages: langs,
+		dbg:       dbg,
+	}
+
+	ws, err := s.Find(f)
+	if err != nil {
+		return nil, err
+	}
+
+	m := browseModel{
+		S:       srv,
+		Words:   ws,
+		Columns: makeColumns(langs),
+		Input:   textinput.New(),
+	}
+	m.List = makeList(m.Columns)
+	m.sort()
+
+	srv.UI = tea.NewProgram(m, tea.WithAltScreen())
+
+	return srv, nil
+}
+
+// Run starts CLI interface
+func (srv *Srv) Run() error {
+	return srv.UI.Start()
+}
+
+const (
+	// UI modes
+	browseMode  = iota // move over the list and search
+	editMode           // edit a field of the selected word
+	confirmMode        // confirm delete or reset of the selected word
+)
+
+const (
+	// editMode fields
+	fieldTranslation = iota
+	fieldTags
+)
+
+var keys = struct {
+	Sort, Reverse, Edit, Tags, Delete, Reset, Details key.Binding
+}{
+	Sort:    key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "sort by next column")),
+	Reverse: key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "reverse order")),
+	Edit:    key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit translation")),
+	Tags:    key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "edit tags")),
+	Delete:  key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "delete")),
+	Reset:   key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "reset progress")),
+	Details: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "details")),
+}
+
+type browseModel struct {
+	S *Srv
+
+	List  list.Model
+	Words store.Words
+
+	Columns []column
+	// SortBy is index of the column words are sorted by
+	SortBy int
+	Desc   bool
+
+	Mode int
+	// Field is edited field in editMode
+	Field int
+	Input textinput.Model
+	// Action is confirmed one in confirmMode: "delete" or "reset"
+	Action string
+
+	// Details shows everything about the selected word
+	Details bool
+
+	Width, Height int
+
+	CurrErr error
+}
+
+func (m browseModel) GetCurrErr() string {
+	if m.CurrErr != nil {
+		return m.CurrErr.Error()
+	}
+	return ""
+}
+
+func (m browseModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m browseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width, m.Height = msg.Width, msg.Height
+		m.resize()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.Mode {
+		case editMode:
+			return m.updateEdit(msg)
+		case confirmMode:
+			return m.updateConfirm(msg)
+		}
+
+		// all keys are for the search input while filtering
+		if m.List.FilterState() == list.Filtering {
+			break
+		}
+
+		w := m.selected()
+		switch {
+		case key.Matches(msg, keys.Sort):
+			m.SortBy = (m.SortBy + 1) % len(m.Columns)
+			return m, m.sort()
+
+		case key.Matches(msg, keys.Reverse):
+			m.Desc = !m.Desc
+			return m, m.sort()
+
+		case key.Matches(msg, keys.Details):
+			m.Details = !m.Details
+			m.resize()
+			return m, nil
+
+		case key.Matches(msg, keys.Edit) && w != nil:
+			m.startEdit(fieldTranslation, w.TranslationIn(m.S.Languages...))
+			return m, textinput.Blink
+
+		case key.Matches(msg, keys.Tags) && w != nil:
+			m.startEdit(fieldTags, strings.Join(w.Tags, ", "))
+			return m, textinput.Blink
+
+		case key.Matches(msg, keys.Delete) && w != nil:
+			m.Mode, m.Action = confirmMode, "delete"
+			return m, nil
+
+		case key.Matches(msg, keys.Reset) && w != nil:
+			m.Mode, m.Action = confirmMode, "reset"
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.List, cmd = m.List.Update(msg)
+	// details height depends on the selected word
+	m.resize()
+	return m, cmd
+}
+
+// updateEdit handles editMode: enter saves the field, esc cancels
+func (m browseModel) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+
+	case tea.KeyEsc:
+		m.stopEdit()
+		return m, nil
+
+	case tea.KeyEnter:
+		w := m.selected()
+		switch m.Field {
+		case fieldTranslation:
+			w.SetTranslationIn(m.Input.Value(), m.S.Languages...)
+		case fieldTags:
+			w.Tags = store.ParseTags(m.Input.Value())
+		}
+		m.CurrErr = m.S.Store.Save(w)
+		m.stopEdit()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+	return m, cmd
+}
+
+// updateConfirm handles confirmMode: "y" runs the Action, anything else cancels it
+func (m browseModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.Mode = browseMode
+	if msg.String() != "y" {
+		return m, nil
+	}
+
+	w := m.selected()
+	switch m.Action {
+	case "delete":
+		m.CurrErr = m.S.Store.Delete(w.Origin)
+		if m.CurrErr != nil {
+			return m, nil
+		}
+		for i := range m.Words {
+			if m.Words[i] == w {
+				m.Words = append(m.Words[:i], m.Words[i+1:]...)
+				break
+			}
+		}
+		return m, m.setItems()
+
+	case "reset":
+		w.ResetProgress()
+		m.CurrErr = m.S.Store.Save(w)
+	}
+	return m, nil
+}
+
+func (m *browseModel) startEdit(field int, val string) {
+	m.Mode, m.Field = editMode, field
+	m.Input.SetValue(val)
+	m.Input.CursorEnd()
+	m.Input.Focus()
+}
+
+func (m *browseModel) stopEdit() {
+	m.Mode = browseMode
+	m.Input.Blur()
+	m.Input.Reset()
+}
+
+// selected returns the word under the cursor, or nil if there are no words
+func (m browseModel) selected() *store.Word {
+	if it, ok := m.List.SelectedItem().(item); ok {
+		return it.w
+	}
+	return nil
+}
+
+// sort sorts words by the current column and shows them
+func (m *browseModel) sort() tea.Cmd {
+	sortWords(m.Words, m.Columns[m.SortBy], m.Desc)
+	m.List.Title = row(m.Columns, func(c column) string {
+		if c.title != m.Columns[m.SortBy].title {
+			return c.title
+		}
+		if m.Desc {
+			return c.title + " ↓"
+		}
+		return c.title + " ↑"
+	})
+	return m.setItems()
+}
+
+func (m *browseModel) setItems() tea.Cmd {
+	items := make([]list.Item, len(m.Words))
+	for i, w := range m.Words {
+		items[i] = item{w: w, langs: m.S.Languages}
+	}
+	return m.List.SetItems(items)
+}
+
+// resize fits the list into the window, leaving space for other widgets
+func (m *browseModel) resize() {
+	h := m.Height - lipgloss.Height(m.titleWidget()) - lipgloss.Height(m.statusWidget())
+	if m.Details {
+		h -= lipgloss.Height(m.detailsWidget())
+	}
+	m.List.SetSize(m.Width, h)
+}
+
+func (m browseModel) View() string {
+	frame := []string{m.titleWidget(), m.List.View()}
+	if m.Details {
+		frame = append(frame, m.detailsWidget())
+	}
+	frame = append(frame, m.statusWidget())
+
+	if m.S.dbg {
+		frame = append(frame, widgets.DebugWidget(m))
+	}
+	return strings.Join(frame, "\n")
+}
+
+func (m browseModel) titleWidget() string {
+	return fmt.Sprintf(">>> Karten 🃏  %d words\n", len(m.Words))
+}
+
+func (m browseModel) detailsWidget() string {
+	w := m.selected()
+	if w == nil {
+		return ""
+	}
+	return widgets.WordWidget(w, m.S.Languages...)
+}
+
+func (m browseModel) statusWidget() string {
+	w := m.selected()
+	switch {
+	case m.Mode == editMode:
+		title := "translation"
+		if m.Field == fieldTags {
+			title = "tags"
+		}
+		return helpStyle(title+": ") + m.Input.View() + helpStyle("  enter: save • esc: cancel")
+
+	case m.Mode == confirmMode && w != nil:
+		return fmt.Sprintf("%s %q? %s", m.Action, w.Origin, helpStyle("y: yes • any key: no"))
+
+	case m.CurrErr != nil:
+		return errStyle("ERR: " + m.CurrErr.Error())
+	}
+	return ""
+}
+
+// item is a list item of store.Word, search is over origin and translation in the preferred languages
+type item struct {
+	w     *store.Word
+	langs []string
+}
+
+func (i item) FilterValue() string {
+	return i.w.Origin + " " + i.w.TranslationIn(i.langs...)
+}
+
+// delegate renders items as table rows of the columns
+type delegate struct {
+	columns []column
+}
+
+func (d delegate) Height() int                             { return 1 }
+func (d delegate) Spacing() int                            { return 0 }
+func (d delegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+
+func (d delegate) Render(w io.Writer, m list.Model, index int, it list.Item) {
+	i, ok := it.(item)
+	if !ok {
+		return
+	}
+
+	s := row(d.columns, func(c column) string { return c.value(i.w) })
+	if index == m.Index() {
+		s = selectedStyle("> " + s)
+	} else {
+		s = "  " + s
+	}
+	_, _ = fmt.Fprint(w, s)
+}
+
+func makeList(columns []column) list.Model {
+	l := list.New(nil, delegate{columns: columns}, 0, 0)
+	l.Styles.Title = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	l.Styles.TitleBar = lipgloss.NewStyle().PaddingLeft(2)
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.Edit, keys.Delete, keys.Sort}
+	}
+	l.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.Sort, keys.Reverse, keys.Edit, keys.Tags, keys.Delete, keys.Reset, keys.Details}
+	}
+	return l
+}
@@ -0,0 +1,95 @@
+package browse
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/egregors/karten/pkg/store"
+	"github.com/mattn/go-runewidth"
+)
+
+// column is a table column: how to render and sort words by it
+type column struct {
+	title string
+	width int
+	value func(w *store.Word) string
+	less  func(a, b *store.Word) bool
+}
+
+// makeColumns makes the table columns, translations are shown in the first of languages words have
+func makeColumns(langs []string) []column {
+	return []column{
+		{
+			title: "ORIGIN",
+			width: 22,
+			value: func(w *store.Word) string { return w.Origin },
+			less:  func(a, b *store.Word) bool { return strings.ToLower(a.Origin) < strings.ToLower(b.Origin) },
+		},
+		{
+			title: "TRANSLATION",
+			width: 32,
+			value: func(w *store.Word) string { return w.TranslationIn(langs...) },
+			less:  func(a, b *store.Word) bool { return a.TranslationIn(langs...) < b.TranslationIn(langs...) },
+		},
+		{
+			title: "SCORE",
+			width: 7,
+			value: func(w *store.Word) string { return strconv.Itoa(w.Score) },
+			less:  func(a, b *store.Word) bool { return a.Score < b.Score },
+		},
+		{
+			title: "SEEN",
+			width: 10,
+			value: func(w *store.Word) string { return formatDate(w.LastSeenAt) },
+			less:  func(a, b *store.Word) bool { return a.LastSeenAt.Before(b.LastSeenAt) },
+		},
+		{
+			title: "ADDED",
+			width: 10,
+			value: func(w *store.Word) string { return formatDate(w.AddedAt) },
+			less:  func(a, b *store.Word) bool { return a.AddedAt.Before(b.AddedAt) },
+		},
+		{
+			title: "DECK",
+			width: 10,
+			value: func(w *store.Word) string { return w.Deck },
+			less:  func(a, b *store.Word) bool { return a.Deck < b.Deck },
+		},
+		{
+			title: "TAGS",
+			width: 20,
+			value: func(w *store.Word) string { return strings.Join(w.Tags, ", ") },
+			less:  func(a, b *store.Word) bool { return strings.Join(a.Tags, ",") < strings.Join(b.Tags, ",") },
+		},
+	}
+}
+
+// sortWords sorts words by the column, stable to keep the store order of equal ones
+func sortWords(ws []*store.Word, col column, desc bool) {
+	less := col.less
+	sort.SliceStable(ws, func(i, j int) bool {
+		if desc {
+			return less(ws[j], ws[i])
+		}
+		return less(ws[i], ws[j])
+	})
+}
+
+// row renders cells of the columns as a table row
+func row(columns []column, cells func(c column) string) string {
+	var b strings.Builder
+	for _, c := range columns {
+		b.WriteString(runewidth.FillRight(runewidth.Truncate(cells(c), c.width, "…"), c.width))
+		b.WriteString("  ")
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Format("2006-01-02")
+}
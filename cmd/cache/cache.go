@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/egregors/karten/pkg/provider"
+)
+
+// Actions of the service
+const (
+	ActionStats = "stats"
+	ActionClear = "clear"
+)
+
+// Manager manages cache of provider lookups
+type Manager interface {
+	// Stats counts cached lookups
+	Stats() (provider.CacheStats, error)
+	// Clear removes all cached lookups
+	Clear() error
+}
+
+// Srv is service to manage cache of provider lookups
+type Srv struct {
+	Cache  Manager
+	Action string
+
+	Out io.Writer
+}
+
+// NewSrv creates a new service to run the action on the cache
+func NewSrv(m Manager, action string, out io.Writer) *Srv {
+	return &Srv{
+		Cache:  m,
+		Action: action,
+		Out:    out,
+	}
+}
+
+// Run runs the action
+func (srv *Srv) Run() error {
+	switch srv.Action {
+	case ActionStats:
+		st, err := srv.Cache.Stats()
+		if err != nil {
+			return fmt.Errorf("can't read cache: %w", err)
+		}
+		srv.printf("entries:   %d\n", st.Entries)
+		srv.printf("not found: %d\n", st.NotFound)
+		srv.printf("expired:   %d\n", st.Expired)
+		srv.printf("size:      %.1f KiB\n", float64(st.Size)/1024)
+		if st.Entries > 0 {
+			srv.printf("oldest:    %s\n", st.Oldest.Format(time.RFC822))
+			srv.printf("newest:    %s\n", st.Newest.Format(time.RFC822))
+		}
+		return nil
+
+	case ActionClear:
+		if err := srv.Cache.Clear(); err != nil {
+			return fmt.Errorf("can't clear cache: %w", err)
+		}
+		srv.printf("cache cleared\n")
+		return nil
+	}
+
+	return fmt.Errorf("unknown action %q", srv.Action)
+}
+
+func (srv *Srv) printf(format string, a ...any) {
+	_, _ = fmt.Fprintf(srv.Out, format, a...)
+}
@@ -0,0 +1,88 @@
+package dict
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Actions of the service
+const (
+	ActionImport = "import"
+	ActionSearch = "search"
+)
+
+// DefaultLimit is max number of found words by default
+const DefaultLimit = 20
+
+// Dictionary is offline dictionary
+type Dictionary interface {
+	// Import replaces the dictionary by the dump and indexes it, returns number of words
+	Import(path, format string) (int, error)
+	// Search returns up to limit words starting with the prefix
+	Search(prefix string, limit int) ([]string, error)
+}
+
+// Opts are arguments of the action
+type Opts struct {
+	// Path is dump file to import
+	Path string
+	// Format is format of the dump, guessed by the file extension if empty
+	Format string
+	// Prefix is start of words to search
+	Prefix string
+	// Limit is max number of found words
+	Limit int
+}
+
+// Srv is service to manage offline dictionary
+type Srv struct {
+	Dict   Dictionary
+	Action string
+	Opts
+
+	Out io.Writer
+}
+
+// NewSrv creates a new service to run the action on the dictionary
+func NewSrv(d Dictionary, action string, opts Opts, out io.Writer) *Srv {
+	if opts.Limit < 1 {
+		opts.Limit = DefaultLimit
+	}
+	return &Srv{
+		Dict:   d,
+		Action: action,
+		Opts:   opts,
+		Out:    out,
+	}
+}
+
+// Run runs the action
+func (srv *Srv) Run() error {
+	switch srv.Action {
+	case ActionImport:
+		started := time.Now()
+		n, err := srv.Dict.Import(srv.Path, srv.Format)
+		if err != nil {
+			return err
+		}
+		srv.printf("imported %d words in %s\n", n, time.Since(started).Round(time.Millisecond))
+		return nil
+
+	case ActionSearch:
+		words, err := srv.Dict.Search(srv.Prefix, srv.Limit)
+		if err != nil {
+			return err
+		}
+		for _, w := range words {
+			srv.printf("%s\n", w)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown action %q", srv.Action)
+}
+
+func (srv *Srv) printf(format string, a ...any) {
+	_, _ = fmt.Fprintf(srv.Out, format, a...)
+}
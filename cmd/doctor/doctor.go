@@ -0,0 +1,72 @@
+package doctor
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+// Srv is service to check and repair the store integrity
+type Srv struct {
+	Store *store.CSV
+	Log   *store.ReviewLog
+
+	// Fix repairs found problems
+	Fix bool
+
+	Out io.Writer
+}
+
+// NewSrv creates a new service to check the store and review log
+func NewSrv(s *store.CSV, l *store.ReviewLog, fix bool, out io.Writer) *Srv {
+	return &Srv{
+		Store: s,
+		Log:   l,
+		Fix:   fix,
+		Out:   out,
+	}
+}
+
+// Run prints all problems found and repairs them in fix mode
+func (srv *Srv) Run() error {
+	ps, err := store.Check(srv.Store, srv.Log)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range ps {
+		srv.printf("%s\n", p)
+	}
+
+	if len(ps) == 0 {
+		srv.printf("no problems found\n")
+		return nil
+	}
+	srv.printf("\n%d problems found\n", len(ps))
+
+	if !srv.Fix {
+		srv.printf("run with --fix to repair them\n")
+		return nil
+	}
+
+	backups, err := store.Repair(srv.Store, srv.Log)
+	for _, b := range backups {
+		srv.printf("backup: %s\n", b)
+	}
+	if err != nil {
+		return fmt.Errorf("can't repair the store: %w", err)
+	}
+
+	ps, err = store.Check(srv.Store, srv.Log)
+	if err != nil {
+		return err
+	}
+	srv.printf("repaired, %d problems left\n", len(ps))
+
+	return nil
+}
+
+func (srv *Srv) printf(format string, a ...any) {
+	_, _ = fmt.Fprintf(srv.Out, format, a...)
+}
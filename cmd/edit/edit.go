@@ -0,0 +1,103 @@
+package edit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/egregors/karten/pkg/store"
+	"github.com/egregors/karten/pkg/widgets"
+)
+
+// WordEditor is store able to get and save a word
+type WordEditor interface {
+	// Get returns store.Word by origin
+	Get(origin string) (*store.Word, error)
+	// Save commit store.Word in the store
+	Save(w *store.Word) error
+}
+
+// Changes are the Word fields to update, nil fields are kept as is
+type Changes struct {
+	Translation, Deck, Example, Mnemonic, Notes *string
+
+	AddTags, RemoveTags []string
+
+	Suspend, Unsuspend bool
+	// Reset forgets learning progress
+	Reset bool
+}
+
+// ErrSuspendConflict is returned if the word is suspended and unsuspended at once
+var ErrSuspendConflict = errors.New("can't suspend and unsuspend the word at once")
+
+// Apply updates the Word
+func (c Changes) Apply(w *store.Word) {
+	if c.Translation != nil {
+		w.SetTranslation(*c.Translation)
+	}
+	if c.Deck != nil {
+		w.Deck = *c.Deck
+	}
+	if c.Example != nil {
+		w.Example = *c.Example
+	}
+	if c.Mnemonic != nil {
+		w.Mnemonic = *c.Mnemonic
+	}
+	if c.Notes != nil {
+		w.Notes = *c.Notes
+	}
+
+	w.RemoveTags(c.RemoveTags...)
+	w.AddTags(c.AddTags...)
+
+	if c.Suspend {
+		w.Suspended = true
+	}
+	if c.Unsuspend {
+		w.Suspended = false
+	}
+	if c.Reset {
+		w.ResetProgress()
+	}
+}
+
+// Srv is service to edit a particular word
+type Srv struct {
+	Store   WordEditor
+	Origin  string
+	Changes Changes
+
+	Out io.Writer
+}
+
+// NewSrv creates a new service to edit the word
+func NewSrv(s WordEditor, origin string, c Changes, out io.Writer) *Srv {
+	return &Srv{
+		Store:   s,
+		Origin:  origin,
+		Changes: c,
+		Out:     out,
+	}
+}
+
+// Run updates the word and prints the result
+func (srv *Srv) Run() error {
+	if srv.Changes.Suspend && srv.Changes.Unsuspend {
+		return ErrSuspendConflict
+	}
+
+	w, err := srv.Store.Get(srv.Origin)
+	if err != nil {
+		return err
+	}
+
+	srv.Changes.Apply(w)
+	if err := srv.Store.Save(w); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(srv.Out, widgets.WordWidget(w))
+	return err
+}
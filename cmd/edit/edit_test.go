@@ -0,0 +1,42 @@
+package edit
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+type memStore struct {
+	word  *store.Word
+	saved bool
+}
+
+func (m *memStore) Get(string) (*store.Word, error) { return m.word, nil }
+
+func (m *memStore) Save(*store.Word) error {
+	m.saved = true
+	return nil
+}
+
+func TestSrv_RunSuspendConflict(t *testing.T) {
+	s := &memStore{word: &store.Word{Origin: "gehen", Suspended: true}}
+	err := NewSrv(s, "gehen", Changes{Suspend: true, Unsuspend: true}, io.Discard).Run()
+	if !errors.Is(err, ErrSuspendConflict) {
+		t.Errorf("got %v, want %v", err, ErrSuspendConflict)
+	}
+	if s.saved || !s.word.Suspended {
+		t.Error("the word is changed")
+	}
+}
+
+func TestSrv_RunUnsuspend(t *testing.T) {
+	s := &memStore{word: &store.Word{Origin: "gehen", Suspended: true}}
+	if err := NewSrv(s, "gehen", Changes{Unsuspend: true}, io.Discard).Run(); err != nil {
+		t.Fatal(err)
+	}
+	if !s.saved || s.word.Suspended {
+		t.Error("the word is not unsuspended")
+	}
+}
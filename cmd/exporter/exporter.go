@@ -0,0 +1,60 @@
+package exporter
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+// WordFinder is store able to search words
+type WordFinder interface {
+	// Find returns all words matching the filter
+	Find(f store.Filter) (store.Words, error)
+}
+
+// Srv is service to export words into a file
+type Srv struct {
+	Store  WordFinder
+	Filter store.Filter
+
+	// Path is file to export into, "-" for stdout
+	Path   string
+	Format string
+
+	Out io.Writer
+}
+
+// NewSrv creates a new service to export words matching the filter
+func NewSrv(s WordFinder, f store.Filter, path, format string, out io.Writer) *Srv {
+	return &Srv{
+		Store:  s,
+		Filter: f,
+		Path:   path,
+		Format: format,
+		Out:    out,
+	}
+}
+
+// Run exports words
+func (srv *Srv) Run() error {
+	ws, err := srv.Store.Find(srv.Filter)
+	if err != nil {
+		return err
+	}
+
+	if srv.Path == "-" {
+		return store.Export(srv.Out, ws, srv.Format)
+	}
+
+	f, err := os.OpenFile(filepath.Clean(srv.Path), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := store.Export(f, ws, srv.Format); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
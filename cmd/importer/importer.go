@@ -0,0 +1,86 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+// WordsAdder is store able to add a bunch of words
+type WordsAdder interface {
+	// AddWords adds new words, skipping already existing ones
+	AddWords(ws ...*store.Word) (int, error)
+}
+
+// Srv is service to import words from a file
+type Srv struct {
+	Store WordsAdder
+
+	// Path is file to import, "-" for stdin
+	Path   string
+	Format string
+	// Deck and Tags are set for all imported words
+	Deck string
+	Tags []string
+
+	In  io.Reader
+	Out io.Writer
+}
+
+// NewSrv creates a new service to import words from the file in particular format.
+// Empty format means guess it by the file extension, stdin is csv by default as export writes it.
+func NewSrv(s WordsAdder, path, format, deck string, tags []string, in io.Reader, out io.Writer) *Srv {
+	switch {
+	case format != "":
+	case path == "-":
+		format = store.FormatCSV
+	default:
+		format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	return &Srv{
+		Store:  s,
+		Path:   path,
+		Format: format,
+		Deck:   deck,
+		Tags:   tags,
+		In:     in,
+		Out:    out,
+	}
+}
+
+// Run imports words
+func (srv *Srv) Run() error {
+	r := srv.In
+	if srv.Path != "-" {
+		f, err := os.Open(filepath.Clean(srv.Path))
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	ws, err := store.Import(r, srv.Format)
+	if err != nil {
+		return fmt.Errorf("can't read %s: %w", srv.Path, err)
+	}
+
+	for _, w := range ws {
+		if srv.Deck != "" {
+			w.Deck = srv.Deck
+		}
+		w.AddTags(srv.Tags...)
+	}
+
+	n, err := srv.Store.AddWords(ws...)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(srv.Out, "imported %d words, %d skipped as already existing\n", n, len(ws)-n)
+	return err
+}
@@ -0,0 +1,51 @@
+package importer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+type memStore struct {
+	words store.Words
+}
+
+func (m *memStore) AddWords(ws ...*store.Word) (int, error) {
+	m.words = append(m.words, ws...)
+	return len(ws), nil
+}
+
+func TestNewSrv_Format(t *testing.T) {
+	tbl := []struct {
+		path, format, want string
+	}{
+		{"words.tsv", "", "tsv"},
+		{"words.json", "", "json"},
+		{"-", "", store.FormatCSV},
+		{"-", "tsv", "tsv"},
+		{"words.txt", "csv", "csv"},
+	}
+	for _, tt := range tbl {
+		if got := NewSrv(nil, tt.path, tt.format, "", nil, nil, nil).Format; got != tt.want {
+			t.Errorf("%s %q: got %q, want %q", tt.path, tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestSrv_RunStdin(t *testing.T) {
+	var buf bytes.Buffer
+	if err := store.Export(&buf, store.Words{store.NewWord("Weg"), store.NewWord("weg")}, store.FormatCSV); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &memStore{}
+	var out strings.Builder
+	if err := NewSrv(s, "-", "", "A1", nil, &buf, &out).Run(); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.words) != 2 || s.words[0].Deck != "A1" {
+		t.Errorf("got %v", s.words)
+	}
+}
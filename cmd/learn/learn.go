@@ -1,29 +1,21 @@
 package learn
 
 import (
+	"container/heap"
 	"fmt"
+	"math/rand"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/egregors/karten/pkg/store"
 	"github.com/egregors/karten/pkg/widgets"
 	"github.com/muesli/termenv"
 )
 
-const (
-	sessionSize = 20
-)
-
-var (
-	color       = termenv.EnvColorProfile().Color
-	helpStyle   = termenv.Style{}.Foreground(color("241")).Styled
-	wordStyle   = termenv.Style{}.Foreground(color("150")).Styled
-	finishStyle = termenv.Style{}.Foreground(color("10")).Styled
-
-	goodStyle = termenv.Style{}.Foreground(color("46")).Styled
-	badStyle  = termenv.Style{}.Foreground(color("69")).Styled
-)
+var color = termenv.EnvColorProfile().Color
 
 const (
 	scoreMarkOn  = "⭐️"
@@ -32,47 +24,109 @@ const (
 
 // WordStore is store with store.Word for learning
 type WordStore interface {
-	// GetWords should return n words in score decreasing order
-	GetWords(n int) (*store.Words, error)
+	// GetWords should return n words matching the filter in score decreasing order
+	GetWords(n int, f store.Filter) (*store.Words, error)
 	// Save commit current store.Word in the store
 	Save(w *store.Word) error
 }
 
+// ReviewLogger keeps history of all answers
+type ReviewLogger interface {
+	// Add appends a new review into the log
+	Add(r store.Review) error
+	// RemoveLast removes the latest review from the log
+	RemoveLast() error
+}
+
+// SessionLogger keeps history of all learning sessions
+type SessionLogger interface {
+	// Add appends a new session into the log
+	Add(s store.Session) error
+	// All returns all sessions
+	All() ([]store.Session, error)
+}
+
 // Srv is service to learn words
 type Srv struct {
-	Store WordStore
+	Store    WordStore
+	Log      ReviewLogger
+	Sessions SessionLogger
 
 	UI *tea.Program
 
+	// Typed is a mode when user types translation instead of self-grading
+	Typed bool
+	// Cloze shows words blanked in their examples instead of words themselves
+	Cloze bool
+	Keys  Keys
+	// Languages are preferred languages of translations
+	Languages []string
+
+	// previous are sessions before this one, to compare with
+	previous []store.Session
+
+	styles styles
+	// rnd chooses examples to show
+	rnd *rand.Rand
 	dbg bool
 }
 
-// NewSrv creates a new service to learning words
-func NewSrv(s WordStore, dbg bool) (*Srv, error) {
+// NewSrv creates a new service to learning words. Empty settings are replaced by defaults.
+func NewSrv(s WordStore, l ReviewLogger, ss SessionLogger, opts Opts) (*Srv, error) {
+	opts = opts.withDefaults()
 	srv := &Srv{
-		Store: s,
-		dbg:   dbg,
+		Store:     s,
+		Log:       l,
+		Sessions:  ss,
+		Typed:     opts.Typed,
+		Cloze:     opts.Cloze,
+		Keys:      opts.Keys,
+		Languages: opts.Languages,
+		styles:    newStyles(opts.Colors),
+		rnd:       rand.New(rand.NewSource(time.Now().UnixNano())), //nolint:gosec // it's just examples
+		dbg:       opts.Dbg,
+	}
+
+	ws, err := srv.Store.GetWords(opts.SessionSize, opts.Filter)
+	if err != nil {
+		return nil, err
 	}
 
-	ws, err := srv.Store.GetWords(sessionSize)
+	srv.previous, err = srv.Sessions.All()
 	if err != nil {
 		return nil, err
 	}
 
-	srv.UI = tea.NewProgram(learnModel{
+	m := learnModel{
 		S:         srv,
 		Words:     ws,
 		CurrWord:  ws.Next(),
 		Forgotten: []*store.Word{},
 		Memorized: []*store.Word{},
-	})
+		TextInput: makeTextInput(),
+		StartedAt: time.Now(),
+	}
+	if srv.Cloze {
+		m.TextInput.Placeholder = "Translation or the missed word..."
+	}
+	m.pickExample()
+	srv.UI = tea.NewProgram(m)
 
 	return srv, nil
 }
 
-// Run starts CLI interface
+// Run starts CLI interface, and saves the session when it's over
 func (srv *Srv) Run() error {
-	return srv.UI.Start()
+	m, err := srv.UI.StartReturningModel()
+	if err != nil {
+		return err
+	}
+
+	lm, ok := m.(learnModel)
+	if !ok || len(lm.History) == 0 {
+		return nil
+	}
+	return srv.Sessions.Add(lm.session())
 }
 
 type learnModel struct {
@@ -83,9 +137,44 @@ type learnModel struct {
 
 	Forgotten, Memorized []*store.Word
 
+	// Revealed shows the back of the card (translations)
+	Revealed bool
+	// Example is the example of current word shown on the card, nil if it has none
+	Example *store.Example
+
+	// TextInput is used for answers in typed mode
+	TextInput textinput.Model
+	// LastAnswer is verdict for the last typed answer
+	LastAnswer string
+
+	// History is all answers of the session, the last one could be undone
+	History []answer
+
+	// Editing shows the form to edit current word, Field is focused one
+	Editing bool
+	Fields  []textinput.Model
+	Field   int
+
+	// StartedAt and FinishedAt are the session time, FinishedAt is zero until all words are answered
+	StartedAt, FinishedAt time.Time
+
 	CurrErr error
 }
 
+// answer is the word state before the answer
+type answer struct {
+	word       *store.Word
+	score      int
+	lastSeenAt time.Time
+	remembered bool
+}
+
+const (
+	// edit form fields
+	fieldTranslation = iota
+	fieldNotes
+)
+
 func (m learnModel) GetCurrErr() string {
 	if m.CurrErr != nil {
 		return m.CurrErr.Error()
@@ -94,46 +183,309 @@ func (m learnModel) GetCurrErr() string {
 }
 
 func (m learnModel) Init() tea.Cmd {
+	if m.S.Typed {
+		return tea.Batch(tea.EnterAltScreen, textinput.Blink)
+	}
 	return tea.EnterAltScreen
 }
 
 func (m learnModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.CurrWord == nil {
+		// summary is shown till any key, the last answer still could be undone
+		msg, ok := msg.(tea.KeyMsg)
+		switch {
+		case !ok:
+			return m, nil
+		case m.isUndo(msg):
+			m.undo()
+			if m.S.Typed {
+				return m, textinput.Blink
+			}
+			return m, nil
+		}
 		return m, tea.Quit
 	}
 
+	if m.Editing {
+		return m.updateEdit(msg)
+	}
+
+	if m.S.Typed {
+		return m.updateTyped(msg)
+	}
+
 	if msg, ok := msg.(tea.KeyMsg); ok {
-		switch msg.String() {
-		case "q", "ctrl+c", "esc":
+		switch keyName(msg) {
+		case m.S.Keys.Quit, "ctrl+c", "esc":
 			return m, tea.Quit
 
-		case "up":
+		case m.S.Keys.Flip:
+			m.Revealed = !m.Revealed
+
+		case m.S.Keys.Edit:
+			m.startEdit()
+			return m, textinput.Blink
+
+		case m.S.Keys.Undo:
+			m.undo()
+
+		case m.S.Keys.Forget:
 			// don't remember
-			m.CurrWord.DecScore()
-			m.CurrErr = m.S.Store.Save(m.CurrWord)
-			m.Forgotten = append(m.Forgotten, m.CurrWord)
-			m.CurrWord = m.Words.Next()
+			m.forget()
 
-		case "down":
+		case m.S.Keys.Know:
 			// remember
-			m.CurrWord.IncScore()
-			m.CurrErr = m.S.Store.Save(m.CurrWord)
-			m.Memorized = append(m.Memorized, m.CurrWord)
-			m.CurrWord = m.Words.Next()
+			m.memorize()
 		}
 	}
 
 	return m, nil
 }
 
+// updateTyped handles typed mode, where user should type any of translations
+func (m learnModel) updateTyped(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case typedEditKey:
+			m.TextInput.Blur()
+			m.startEdit()
+			return m, textinput.Blink
+
+		case typedUndoKey:
+			m.undo()
+			return m, cmd
+		}
+
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			return m, tea.Quit
+
+		case tea.KeyEnter:
+			answer := m.TextInput.Value()
+			w := m.CurrWord
+			if _, ok := m.cloze(); ok {
+				m.checkCloze(answer)
+				m.TextInput.Reset()
+				return m, cmd
+			}
+			if w.CheckAnswer(answer) {
+				m.LastAnswer = m.S.styles.good("✓ ") + w.Origin + " – " + m.translation(w)
+				m.memorize()
+			} else {
+				m.LastAnswer = m.S.styles.bad("✗ ") + w.Origin + " – " + m.translation(w) + m.S.styles.help(" (not "+answer+")")
+				m.forget()
+			}
+			m.TextInput.Reset()
+			return m, cmd
+		}
+	}
+
+	m.TextInput, cmd = m.TextInput.Update(msg)
+	return m, cmd
+}
+
+// updateEdit handles the edit form: enter saves the word, esc cancels
+func (m learnModel) updateEdit(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.Type {
+		case tea.KeyCtrlC:
+			return m, tea.Quit
+
+		case tea.KeyEsc:
+			m.stopEdit()
+			return m, cmd
+
+		case tea.KeyUp, tea.KeyDown, tea.KeyTab:
+			m.Fields[m.Field].Blur()
+			m.Field = (m.Field + 1) % len(m.Fields)
+			m.Fields[m.Field].Focus()
+			return m, cmd
+
+		case tea.KeyEnter:
+			w := m.CurrWord
+			if t := strings.TrimSpace(m.Fields[fieldTranslation].Value()); t != "" {
+				w.SetTranslationIn(t, m.S.Languages...)
+			}
+			w.Notes = strings.TrimSpace(m.Fields[fieldNotes].Value())
+			m.CurrErr = m.S.Store.Save(w)
+			m.stopEdit()
+			m.Revealed = true
+			return m, cmd
+		}
+	}
+
+	m.Fields[m.Field], cmd = m.Fields[m.Field].Update(msg)
+	return m, cmd
+}
+
+// startEdit shows the edit form filled by current word
+func (m *learnModel) startEdit() {
+	m.Editing = true
+	m.Field = fieldTranslation
+	m.Fields = makeFields(m.CurrWord, m.S.Languages)
+}
+
+func (m *learnModel) stopEdit() {
+	m.Editing = false
+	m.Fields = nil
+	if m.S.Typed {
+		m.TextInput.Focus()
+	}
+}
+
+// undo reverts the last answer: restores the word progress and makes it current again
+func (m *learnModel) undo() {
+	if len(m.History) == 0 {
+		return
+	}
+	a := m.History[len(m.History)-1]
+	m.History = m.History[:len(m.History)-1]
+
+	a.word.Score, a.word.LastSeenAt = a.score, a.lastSeenAt
+	if err := m.S.Store.Save(a.word); err != nil {
+		m.CurrErr = err
+		return
+	}
+	m.CurrErr = m.S.Log.RemoveLast()
+
+	if a.remembered {
+		m.Memorized = m.Memorized[:len(m.Memorized)-1]
+	} else {
+		m.Forgotten = m.Forgotten[:len(m.Forgotten)-1]
+	}
+
+	if m.CurrWord != nil {
+		heap.Push(m.Words, m.CurrWord)
+	}
+	m.CurrWord = a.word
+	m.FinishedAt = time.Time{}
+	m.Revealed = false
+	m.LastAnswer = ""
+	m.pickExample()
+}
+
+// isUndo checks if the key is undo one for the current mode
+func (m learnModel) isUndo(msg tea.KeyMsg) bool {
+	if m.S.Typed {
+		return msg.String() == typedUndoKey
+	}
+	return keyName(msg) == m.S.Keys.Undo
+}
+
+// remember keeps current word state to undo the answer
+func (m *learnModel) remember(remembered bool) {
+	m.History = append(m.History, answer{
+		word:       m.CurrWord,
+		score:      m.CurrWord.Score,
+		lastSeenAt: m.CurrWord.LastSeenAt,
+		remembered: remembered,
+	})
+}
+
+// forget moves current word to forgotten ones, and takes next one
+func (m *learnModel) forget() {
+	m.remember(false)
+	m.logReview(false)
+	m.CurrWord.DecScore()
+	m.CurrErr = m.S.Store.Save(m.CurrWord)
+	m.Forgotten = append(m.Forgotten, m.CurrWord)
+	m.next()
+}
+
+// memorize moves current word to memorized ones, and takes next one
+func (m *learnModel) memorize() {
+	m.remember(true)
+	m.logReview(true)
+	m.CurrWord.IncScore()
+	m.CurrErr = m.S.Store.Save(m.CurrWord)
+	m.Memorized = append(m.Memorized, m.CurrWord)
+	m.next()
+}
+
+// logReview writes the answer on current word into review log
+func (m *learnModel) logReview(remembered bool) {
+	err := m.S.Log.Add(store.Review{
+		At:         time.Now(),
+		Origin:     m.CurrWord.Origin,
+		Remembered: remembered,
+		Score:      m.CurrWord.Score,
+	})
+	if err != nil {
+		m.CurrErr = err
+	}
+}
+
+func (m *learnModel) next() {
+	m.CurrWord = m.Words.Next()
+	m.Revealed = false
+	if m.CurrWord == nil {
+		m.FinishedAt = time.Now()
+	}
+	m.pickExample()
+}
+
+// pickExample chooses a random example of current word, in cloze mode – of ones the word could be blanked in
+func (m *learnModel) pickExample() {
+	m.Example = nil
+	if m.CurrWord == nil {
+		return
+	}
+
+	var es []store.Example
+	for _, e := range m.CurrWord.Examples {
+		if _, ok := e.Cloze(m.CurrWord.Origin); ok || !m.S.Cloze {
+			es = append(es, e)
+		}
+	}
+	if len(es) > 0 {
+		m.Example = &es[m.S.rnd.Intn(len(es))]
+	}
+}
+
+// cloze returns the example of current word with the word blanked, false if it's not cloze mode or no example
+func (m learnModel) cloze() (string, bool) {
+	if !m.S.Cloze || m.Example == nil {
+		return "", false
+	}
+	return m.Example.Cloze(m.CurrWord.Origin)
+}
+
+// checkCloze checks typed answer to cloze: the missed word
+func (m *learnModel) checkCloze(answer string) {
+	w, e := m.CurrWord, m.Example
+	if e.CheckCloze(answer, w.Origin) {
+		m.LastAnswer = m.S.styles.good("✓ ") + e.Text
+		m.memorize()
+		return
+	}
+	m.LastAnswer = m.S.styles.bad("✗ ") + e.Text + m.S.styles.help(" (not "+answer+")")
+	m.forget()
+}
+
 func (m learnModel) View() string {
+	if m.CurrWord == nil {
+		frame := []string{m.titleWidget()}
+		if m.S.Typed {
+			frame = append(frame, "    "+m.LastAnswer+"\n")
+		}
+		frame = append(frame, m.summaryWidget(), m.helpWidget())
+		return strings.Join(frame, "\n")
+	}
+
 	frame := []string{
 		m.titleWidget(),
 		m.forgottenWidget(),
 		m.wordWidget(),
+	}
+	if m.S.Typed {
+		frame = append(frame, m.answerWidget())
+	}
+	frame = append(frame,
 		m.memorizedWidget(),
 		m.helpWidget(),
-	}
+	)
 
 	if m.S.dbg {
 		frame = append(frame, widgets.DebugWidget(m))
@@ -158,21 +510,78 @@ func (m learnModel) titleWidget() string {
 }
 
 func (m learnModel) wordWidget() string {
-	if m.CurrWord == nil {
-		return fmt.Sprintf(
-			"    %s  %s / %s\n",
-			finishStyle("Nice!"),
-			goodStyle(strconv.Itoa(len(m.Memorized))),
-			badStyle(strconv.Itoa(len(m.Forgotten))))
+	front := m.S.styles.word(m.CurrWord.Origin)
+	// cloze is the front of the card, the word is shown with the back
+	if c, ok := m.cloze(); ok && !m.Revealed {
+		front = m.S.styles.word(c)
+		if t := m.Example.TranslationIn(m.S.Languages...); t != "" {
+			front += "\n" + strings.Repeat(" ", 16) + m.S.styles.help(t)
+		}
+	}
+	s := fmt.Sprintf("    %s  %s\n", m.getScoreStars(), front)
+	if m.Editing {
+		return s + m.editWidget()
+	}
+	if m.Revealed {
+		s += m.backWidget()
 	}
+	return s
+}
 
-	return fmt.Sprintf("    %s  %s\n", m.getScoreStars(), wordStyle(m.CurrWord.Origin))
+// backWidget is the back of the card: list of word senses and user's notes
+func (m learnModel) backWidget() string {
+	w := m.CurrWord
+	var s string
+	for i, sense := range w.SensesIn(m.S.Languages...) {
+		s += fmt.Sprintf("      %d. %s\n", i+1, sense)
+		if sense.Example != "" {
+			s += m.S.styles.help("         "+sense.Example) + "\n"
+		}
+	}
+
+	if e := m.Example; e != nil {
+		s += "\n      » " + e.Text + "\n"
+		if t := e.TranslationIn(m.S.Languages...); t != "" {
+			s += "        " + m.S.styles.help(t) + "\n"
+		}
+	}
+
+	if len(w.Conjugation) > 0 {
+		for _, line := range strings.Split(w.Conjugation.Grid(), "\n") {
+			s += "\n      " + m.S.styles.help(line)
+		}
+		s += "\n"
+	}
+
+	notes := []struct{ title, val string }{
+		{"example", w.Example},
+		{"mnemonic", w.Mnemonic},
+		{"notes", w.Notes},
+	}
+	for _, n := range notes {
+		if n.val != "" {
+			s += "\n      " + m.S.styles.help(n.title+": ") + n.val
+		}
+	}
+	return s + "\n"
+}
+
+func (m learnModel) editWidget() string {
+	return fmt.Sprintf(
+		"      %s%s\n      %s%s\n",
+		m.S.styles.help("translation: "), m.Fields[fieldTranslation].View(),
+		m.S.styles.help("notes:       "), m.Fields[fieldNotes].View(),
+	)
+}
+
+func (m learnModel) answerWidget() string {
+	return "    " + m.TextInput.View() + "\n\n    " + m.LastAnswer + "\n"
 }
 
 func (m learnModel) forgottenWidget() string {
 	ws := make([]string, len(m.Forgotten))
 	for i, w := range m.Forgotten {
-		ws[i] = fmt.Sprintf("    %s - %s", w.Origin, w.Translation)
+		ws[i] = fmt.Sprintf("    %s - %s", w.Origin, m.translation(w))
 	}
 	return strings.Join(ws, "\n") + "\n"
 }
@@ -186,12 +595,68 @@ func (m learnModel) memorizedWidget() string {
 	var ws []string
 	// todo: extract 5 to consts
 	for i := len(m.Memorized) - 1; i >= 0 && len(m.Memorized)-i <= 5; i-- {
-		ws = append(ws, s(start, fmt.Sprintf("    %s - %s", m.Memorized[i].Origin, m.Memorized[i].Translation)))
+		ws = append(ws, s(start, fmt.Sprintf("    %s - %s", m.Memorized[i].Origin, m.translation(m.Memorized[i]))))
 		start -= 3
 	}
 	return strings.Join(ws, "\n")
 }
 
 func (m learnModel) helpWidget() string {
-	return helpStyle("\n  up: I know it! • down: i don't remember :( • q | ctrl+c | esc: exit\n")
+	if m.CurrWord == nil {
+		undo := m.S.Keys.Undo
+		if m.S.Typed {
+			undo = typedUndoKey
+		}
+		return m.S.styles.help(fmt.Sprintf("\n  any key: exit • %s: undo the last answer\n", undo))
+	}
+	if m.Editing {
+		return m.S.styles.help("\n  enter: save • tab: next field • esc: cancel\n")
+	}
+	if m.S.Typed {
+		return m.S.styles.help(fmt.Sprintf(
+			"\n  enter: check translation • %s: edit • %s: undo • ctrl+c | esc: exit\n",
+			typedEditKey, typedUndoKey,
+		))
+	}
+	k := m.S.Keys
+	return m.S.styles.help(fmt.Sprintf(
+		"\n  %s: I know it! • %s: i don't remember :( • %s: flip the card • %s: edit • %s: undo • %s | ctrl+c | esc: exit\n",
+		k.Know, k.Forget, k.Flip, k.Edit, k.Undo, k.Quit,
+	))
+}
+
+// keyName returns bubbletea key name, space is named as "space"
+func keyName(msg tea.KeyMsg) string {
+	if msg.String() == " " {
+		return "space"
+	}
+	return msg.String()
+}
+
+// translation returns translation of the word in the preferred language
+func (m learnModel) translation(w *store.Word) string {
+	return w.TranslationIn(m.S.Languages...)
+}
+
+// makeFields makes the edit form filled by the word translation in the preferred language
+func makeFields(w *store.Word, langs []string) []textinput.Model {
+	fs := make([]textinput.Model, 2)
+	for i := range fs {
+		fs[i] = textinput.New()
+		fs[i].CharLimit = 256
+		fs[i].Width = 50
+	}
+	fs[fieldTranslation].SetValue(w.TranslationIn(langs...))
+	fs[fieldNotes].SetValue(w.Notes)
+	fs[fieldTranslation].Focus()
+	return fs
+}
+
+func makeTextInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "Translation..."
+	ti.Focus()
+	ti.CharLimit = 156
+	ti.Width = 40
+	return ti
 }
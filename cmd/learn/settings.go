@@ -0,0 +1,113 @@
+package learn
+
+import (
+	"github.com/egregors/karten/pkg/store"
+	"github.com/muesli/termenv"
+)
+
+const (
+	// DefaultSessionSize is number of words for one session
+	DefaultSessionSize = 20
+)
+
+// Colors are terminal colors of learn UI: ANSI codes ("0"-"255") or hex ("#ff0000")
+type Colors struct {
+	Word, Help, Good, Bad, Finish string
+}
+
+// DefaultColors are colors used if nothing is set
+var DefaultColors = Colors{
+	Word:   "150",
+	Help:   "241",
+	Good:   "46",
+	Bad:    "69",
+	Finish: "10",
+}
+
+// Keys are key bindings of learn UI, like "up", "ctrl+k", "space" or "q"
+type Keys struct {
+	Know, Forget, Flip, Edit, Undo, Quit string
+}
+
+// DefaultKeys are key bindings used if nothing is set
+var DefaultKeys = Keys{
+	Know:   "down",
+	Forget: "up",
+	Flip:   "space",
+	Edit:   "e",
+	Undo:   "u",
+	Quit:   "q",
+}
+
+// Typed mode keys, they are not configurable to keep letters for answers
+const (
+	typedEditKey = "ctrl+e"
+	typedUndoKey = "ctrl+z"
+)
+
+// Opts is settings of the learning session
+type Opts struct {
+	// Filter chooses words for the session, nil for all words
+	Filter      store.Filter
+	SessionSize int
+	// Typed is a mode when user types translation instead of self-grading
+	Typed bool
+	// Cloze shows words blanked in their examples instead of words themselves, if they have examples
+	Cloze bool
+
+	Colors Colors
+	Keys   Keys
+	// Languages are preferred languages of translations, words are shown in the first one they have
+	Languages []string
+
+	Dbg bool
+}
+
+// withDefaults fills empty settings by default values
+func (o Opts) withDefaults() Opts {
+	if o.SessionSize <= 0 {
+		o.SessionSize = DefaultSessionSize
+	}
+
+	or := func(v, def string) string {
+		if v == "" {
+			return def
+		}
+		return v
+	}
+	o.Colors = Colors{
+		Word:   or(o.Colors.Word, DefaultColors.Word),
+		Help:   or(o.Colors.Help, DefaultColors.Help),
+		Good:   or(o.Colors.Good, DefaultColors.Good),
+		Bad:    or(o.Colors.Bad, DefaultColors.Bad),
+		Finish: or(o.Colors.Finish, DefaultColors.Finish),
+	}
+	o.Keys = Keys{
+		Know:   or(o.Keys.Know, DefaultKeys.Know),
+		Forget: or(o.Keys.Forget, DefaultKeys.Forget),
+		Flip:   or(o.Keys.Flip, DefaultKeys.Flip),
+		Edit:   or(o.Keys.Edit, DefaultKeys.Edit),
+		Undo:   or(o.Keys.Undo, DefaultKeys.Undo),
+		Quit:   or(o.Keys.Quit, DefaultKeys.Quit),
+	}
+
+	return o
+}
+
+// styles are text painters made from Colors
+type styles struct {
+	word, help, good, bad, finish func(string) string
+}
+
+func newStyles(c Colors) styles {
+	style := func(clr string) func(string) string {
+		return termenv.Style{}.Foreground(color(clr)).Styled
+	}
+	return styles{
+		word:   style(c.Word),
+		help:   style(c.Help),
+		good:   style(c.Good),
+		bad:    style(c.Bad),
+		finish: style(c.Finish),
+	}
+}
@@ -0,0 +1,100 @@
+package learn
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+const (
+	// hardestWords is max number of the hardest words in the summary
+	hardestWords = 5
+	// lastSessions is number of previous sessions to compare with
+	lastSessions = 10
+)
+
+// session makes summary of the session
+func (m learnModel) session() store.Session {
+	end := m.FinishedAt
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	s := store.Session{
+		Start:      m.StartedAt,
+		End:        end,
+		Reviewed:   len(m.History),
+		Remembered: len(m.Memorized),
+		Forgotten:  len(m.Forgotten),
+	}
+	for _, a := range m.History {
+		switch {
+		case a.word.Score > a.score:
+			s.Promoted++
+		case a.word.Score < a.score:
+			s.Demoted++
+		}
+	}
+	return s
+}
+
+func (m learnModel) summaryWidget() string {
+	st := m.S.styles
+	s := m.session()
+
+	out := fmt.Sprintf(
+		"    %s  %s / %s\n\n",
+		st.finish("Nice!"),
+		st.good(strconv.Itoa(s.Remembered)),
+		st.bad(strconv.Itoa(s.Forgotten)))
+
+	prev := m.S.previous
+	if len(prev) > lastSessions {
+		prev = prev[len(prev)-lastSessions:]
+	}
+	var accuracy, duration string
+	if len(prev) > 0 {
+		var acc int
+		var d time.Duration
+		for _, p := range prev {
+			acc += p.Accuracy()
+			d += p.Duration()
+		}
+		accuracy = st.help(fmt.Sprintf("avg of last %d: %d%%", len(prev), acc/len(prev)))
+		duration = st.help(fmt.Sprintf("avg of last %d: %s", len(prev), formatDuration(d/time.Duration(len(prev)))))
+	}
+
+	out += fmt.Sprintf("    accuracy   %-8s %s\n", strconv.Itoa(s.Accuracy())+"%", accuracy)
+	out += fmt.Sprintf("    time       %-8s %s\n", formatDuration(s.Duration()), duration)
+	out += fmt.Sprintf("    promoted   %d, demoted %d\n", s.Promoted, s.Demoted)
+	if len(prev) == 0 {
+		out += "\n    " + st.help("your first session, keep going!") + "\n"
+	}
+
+	if hardest := m.hardest(); len(hardest) > 0 {
+		out += "\n    hardest words:\n"
+		for _, w := range hardest {
+			out += fmt.Sprintf("      %s – %s %s\n", st.word(w.Origin), m.translation(w), st.help(fmt.Sprintf("(%d/%d)", w.Score, store.MaxScore)))
+		}
+	}
+
+	return out
+}
+
+// hardest returns forgotten words with the lowest scores
+func (m learnModel) hardest() []*store.Word {
+	ws := make([]*store.Word, len(m.Forgotten))
+	copy(ws, m.Forgotten)
+	sort.SliceStable(ws, func(i, j int) bool { return ws[i].Score < ws[j].Score })
+	if len(ws) > hardestWords {
+		ws = ws[:hardestWords]
+	}
+	return ws
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
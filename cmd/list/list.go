@@ -0,0 +1,60 @@
+package list
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+// WordFinder is store able to search words
+type WordFinder interface {
+	// Find returns all words matching the filter
+	Find(f store.Filter) (store.Words, error)
+}
+
+// Srv is service to print words from the store
+type Srv struct {
+	Store  WordFinder
+	Filter store.Filter
+
+	Out io.Writer
+}
+
+// NewSrv creates a new service to print words matching the filter
+func NewSrv(s WordFinder, f store.Filter, out io.Writer) *Srv {
+	return &Srv{
+		Store:  s,
+		Filter: f,
+		Out:    out,
+	}
+}
+
+// Run prints matched words as a table
+func (srv *Srv) Run() error {
+	ws, err := srv.Store.Find(srv.Filter)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(srv.Out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ORIGIN\tTRANSLATION\tSCORE\tDECK\tTAGS")
+	for _, w := range ws {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			w.Origin,
+			w.Translation(),
+			strconv.Itoa(w.Score),
+			w.Deck,
+			strings.Join(w.Tags, ", "),
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(srv.Out, "\n%d words\n", len(ws))
+	return err
+}
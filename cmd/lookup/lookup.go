@@ -0,0 +1,149 @@
+package lookup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/egregors/karten/pkg/provider"
+	"github.com/egregors/karten/pkg/store"
+)
+
+// CardProvider is dictionary to look up words in
+type CardProvider interface {
+	// Lookup returns provider.Card for the word, the request should be canceled with ctx
+	Lookup(ctx context.Context, query string) (*provider.Card, error)
+}
+
+// WordGetter is store able to get a word by origin
+type WordGetter interface {
+	// Get returns store.Word by origin
+	Get(origin string) (*store.Word, error)
+}
+
+// Srv is service to look up a word without saving it
+type Srv struct {
+	Provider CardProvider
+	Store    WordGetter
+	Query    string
+	// Timeout is max time of the lookup
+	Timeout time.Duration
+
+	// JSON prints the card as JSON
+	JSON bool
+
+	Out io.Writer
+}
+
+// NewSrv creates a new service to look up the word
+func NewSrv(p CardProvider, s WordGetter, query string, timeout time.Duration, asJSON bool, out io.Writer) *Srv {
+	return &Srv{
+		Provider: p,
+		Store:    s,
+		Query:    query,
+		Timeout:  timeout,
+		JSON:     asJSON,
+		Out:      out,
+	}
+}
+
+// result is the card with the word state in the store
+type result struct {
+	*provider.Card
+	InStore bool `json:"in_store"`
+	Score   *int `json:"score,omitempty"`
+}
+
+// Run prints the card of the word
+func (srv *Srv) Run() error {
+	ctx := context.Background()
+	if srv.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, srv.Timeout)
+		defer cancel()
+	}
+
+	card, err := srv.Provider.Lookup(ctx, srv.Query)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("lookup timed out after %s", srv.Timeout)
+	}
+	if err != nil {
+		return err
+	}
+
+	res := result{Card: card}
+	w, err := srv.Store.Get(card.Word())
+	switch {
+	case err == nil:
+		res.InStore, res.Score = true, &w.Score
+	case !errors.Is(err, store.ErrNotFound):
+		return err
+	}
+
+	if srv.JSON {
+		enc := json.NewEncoder(srv.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+
+	s := card.String() + "\n"
+	if g := card.Grammar(); g != "" {
+		s += g + "\n"
+	}
+	for i, sense := range card.Senses {
+		s += fmt.Sprintf("  %d. %s\n", i+1, sense)
+	}
+	for _, e := range card.Examples {
+		s += "\n  » " + e.Text + "\n"
+		if t := e.TranslationIn(card.Lang); t != "" {
+			s += "    " + t + "\n"
+		}
+	}
+	if len(card.Conjugation) > 0 {
+		s += "\n" + indent(card.Conjugation.Grid(), "  ") + "\n"
+	}
+	if len(card.Sources) > 0 {
+		s += "\n" + sources(card.Sources) + "\n"
+	}
+	if res.InStore {
+		s += fmt.Sprintf("\nin your store, score %d/%d\n", w.Score, store.MaxScore)
+	} else {
+		s += "\nnot in your store, add it by `karten add`\n"
+	}
+
+	_, err = fmt.Fprint(srv.Out, s)
+	return err
+}
+
+// sources renders which provider supplied each field of the card, like "senses, forms from verbformen; grammar from offline"
+func sources(ss map[string]string) string {
+	fields := map[string][]string{}
+	var names []string
+	for f, name := range ss {
+		if fields[name] == nil {
+			names = append(names, name)
+		}
+		fields[name] = append(fields[name], f)
+	}
+	sort.Strings(names)
+
+	if len(names) == 1 {
+		return "source: " + names[0]
+	}
+	parts := make([]string, len(names))
+	for i, name := range names {
+		sort.Strings(fields[name])
+		parts[i] = fmt.Sprintf("%s from %s", strings.Join(fields[name], ", "), name)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// indent prefixes every line of s
+func indent(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
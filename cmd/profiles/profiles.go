@@ -0,0 +1,95 @@
+package profiles
+
+import (
+	"fmt"
+	"io"
+)
+
+// Actions of the service
+const (
+	ActionList   = "list"
+	ActionCreate = "create"
+	ActionCopy   = "copy"
+	ActionDelete = "delete"
+)
+
+// Manager manages profiles
+type Manager interface {
+	// List returns names of all profiles
+	List() ([]string, error)
+	// Create makes a new empty profile
+	Create(name string) error
+	// Copy makes a new profile dst with all data of src
+	Copy(src, dst string) error
+	// Delete removes the profile with all its data
+	Delete(name string) error
+}
+
+// Srv is service to manage profiles
+type Srv struct {
+	Profiles Manager
+	// Current is the active profile name
+	Current string
+
+	Action string
+	Names  []string
+
+	Out io.Writer
+}
+
+// NewSrv creates a new service to run the action on profiles with names
+func NewSrv(m Manager, current, action string, names []string, out io.Writer) *Srv {
+	return &Srv{
+		Profiles: m,
+		Current:  current,
+		Action:   action,
+		Names:    names,
+		Out:      out,
+	}
+}
+
+// Run runs the action
+func (srv *Srv) Run() error {
+	switch srv.Action {
+	case ActionList:
+		names, err := srv.Profiles.List()
+		if err != nil {
+			return err
+		}
+		for _, n := range names {
+			mark := " "
+			if n == srv.Current {
+				mark = "*"
+			}
+			srv.printf("%s %s\n", mark, n)
+		}
+		return nil
+
+	case ActionCreate:
+		if err := srv.Profiles.Create(srv.Names[0]); err != nil {
+			return err
+		}
+		srv.printf("created: %s\n", srv.Names[0])
+		return nil
+
+	case ActionCopy:
+		if err := srv.Profiles.Copy(srv.Names[0], srv.Names[1]); err != nil {
+			return err
+		}
+		srv.printf("copied: %s -> %s\n", srv.Names[0], srv.Names[1])
+		return nil
+
+	case ActionDelete:
+		if err := srv.Profiles.Delete(srv.Names[0]); err != nil {
+			return err
+		}
+		srv.printf("deleted: %s\n", srv.Names[0])
+		return nil
+	}
+
+	return fmt.Errorf("unknown action %q", srv.Action)
+}
+
+func (srv *Srv) printf(format string, a ...any) {
+	_, _ = fmt.Fprintf(srv.Out, format, a...)
+}
@@ -0,0 +1,40 @@
+package remove
+
+import (
+	"fmt"
+	"io"
+)
+
+// WordDeleter is store able to delete words
+type WordDeleter interface {
+	// Delete removes store.Word by origin
+	Delete(origin string) error
+}
+
+// Srv is service to delete words
+type Srv struct {
+	Store   WordDeleter
+	Origins []string
+
+	Out io.Writer
+}
+
+// NewSrv creates a new service to delete the words
+func NewSrv(s WordDeleter, origins []string, out io.Writer) *Srv {
+	return &Srv{
+		Store:   s,
+		Origins: origins,
+		Out:     out,
+	}
+}
+
+// Run deletes all the words, it stops on the first error
+func (srv *Srv) Run() error {
+	for _, o := range srv.Origins {
+		if err := srv.Store.Delete(o); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(srv.Out, "deleted: %s\n", o)
+	}
+	return nil
+}
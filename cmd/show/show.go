@@ -0,0 +1,43 @@
+package show
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/egregors/karten/pkg/store"
+	"github.com/egregors/karten/pkg/widgets"
+)
+
+// WordGetter is store able to get a word by origin
+type WordGetter interface {
+	// Get returns store.Word by origin
+	Get(origin string) (*store.Word, error)
+}
+
+// Srv is service to print a particular word
+type Srv struct {
+	Store  WordGetter
+	Origin string
+
+	Out io.Writer
+}
+
+// NewSrv creates a new service to print the word
+func NewSrv(s WordGetter, origin string, out io.Writer) *Srv {
+	return &Srv{
+		Store:  s,
+		Origin: origin,
+		Out:    out,
+	}
+}
+
+// Run prints the word
+func (srv *Srv) Run() error {
+	w, err := srv.Store.Get(srv.Origin)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(srv.Out, widgets.WordWidget(w))
+	return err
+}
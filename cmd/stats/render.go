@@ -0,0 +1,166 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// heat are heatmap cells from no reviews to the most active days
+var heat = []string{"·", "░", "▒", "▓", "█"}
+
+func (r report) overview() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "words:     %d\n", r.total)
+	fmt.Fprintf(&b, "suspended: %d\n", r.suspended)
+	fmt.Fprintf(&b, "new:       %d\n", r.unseen)
+	fmt.Fprintf(&b, "reviews:   %d\n", r.reviews)
+	fmt.Fprintf(&b, "streak:    %d days\n", r.streak)
+	fmt.Fprintf(&b, "sessions:  %d, %s spent, %d%% accuracy on average\n",
+		r.sessions, r.timeSpent.Round(time.Minute), r.accuracy)
+	return b.String()
+}
+
+func (r report) scoresChart() string {
+	var b strings.Builder
+	b.WriteString("scores:\n")
+	for s, n := range r.scores {
+		fmt.Fprintf(&b, "  %d  %5d  %s\n", s, n, bar(n, r.total))
+	}
+	return b.String()
+}
+
+func (r report) decksTable() string {
+	var b strings.Builder
+	b.WriteString("decks:\n")
+	names := make([]string, 0, len(r.decks))
+	for d := range r.decks {
+		names = append(names, d)
+	}
+	sort.Strings(names)
+	for _, d := range names {
+		name := d
+		if name == "" {
+			name = "(no deck)"
+		}
+		fmt.Fprintf(&b, "  %-20s %5d\n", name, r.decks[d])
+	}
+	return b.String()
+}
+
+func (r report) addedChart() string {
+	var b strings.Builder
+	b.WriteString("added per week:\n")
+	week := weekOf(r.now).AddDate(0, 0, -7*(addedWeeks-1))
+	for _, n := range r.addedPerWeek {
+		fmt.Fprintf(&b, "  %s  %5d  %s\n", week.Format("Jan 02"), n, bar(n, max(r.addedPerWeek)))
+		week = week.AddDate(0, 0, 7)
+	}
+	return b.String()
+}
+
+// heatmap draws reviews per day as a calendar: a column per week, a row per weekday
+func (r report) heatmap() string {
+	start := weekOf(r.now).AddDate(0, 0, -7*(heatmapWeeks-1))
+	today := dayOf(r.now)
+
+	top := 0
+	for d, n := range r.reviewsPerDay {
+		if !d.Before(start) && n > top {
+			top = n
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("reviews per day:\n")
+
+	// month labels over the first week of each month
+	months := []byte(strings.Repeat(" ", 2*heatmapWeeks))
+	for w := 0; w < heatmapWeeks; w++ {
+		d := start.AddDate(0, 0, 7*w)
+		if w == 0 || d.Day() <= 7 {
+			copy(months[2*w:], d.Format("Jan"))
+		}
+	}
+	fmt.Fprintf(&b, "      %s\n", strings.TrimRight(string(months), " "))
+
+	for wd := 0; wd < 7; wd++ {
+		label := ""
+		if wd%2 == 0 {
+			label = start.AddDate(0, 0, wd).Format("Mon")
+		}
+		fmt.Fprintf(&b, "  %-3s ", label)
+		for w := 0; w < heatmapWeeks; w++ {
+			d := start.AddDate(0, 0, 7*w+wd)
+			if d.After(today) {
+				break
+			}
+			b.WriteString(heatCell(r.reviewsPerDay[d], top) + " ")
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "      less %s more\n", strings.Join(heat, " "))
+	return b.String()
+}
+
+func (r report) retentionTable() string {
+	var b strings.Builder
+	b.WriteString("retention by interval:\n")
+	for _, bk := range r.retention {
+		if bk.reviews == 0 {
+			fmt.Fprintf(&b, "  %-10s     -\n", bk.name)
+			continue
+		}
+		fmt.Fprintf(&b, "  %-10s %3d%%  %s  (%d of %d)\n",
+			bk.name, bk.rate(), bar(bk.rate(), 100), bk.remembered, bk.reviews)
+	}
+	return b.String()
+}
+
+func (r report) forecastChart() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "due reviews, next %d days:\n", forecastDays)
+	day := dayOf(r.now)
+	for i, n := range r.forecast {
+		label := day.AddDate(0, 0, i).Format("Mon Jan 02")
+		if i == 0 {
+			label = "today     "
+		}
+		fmt.Fprintf(&b, "  %s  %5d  %s\n", label, n, bar(n, max(r.forecast)))
+	}
+	return b.String()
+}
+
+// heatCell returns heatmap cell for n reviews of top ones
+func heatCell(n, top int) string {
+	if n == 0 || top == 0 {
+		return heat[0]
+	}
+	// rounded up, so any reviews are visible
+	levels := len(heat) - 1
+	i := (n*levels + top - 1) / top
+	if i > levels {
+		i = levels
+	}
+	return heat[i]
+}
+
+// bar draws a horizontal bar proportional to n of total
+func bar(n, total int) string {
+	const width = 40
+	if total == 0 {
+		return ""
+	}
+	return strings.Repeat("█", n*width/total)
+}
+
+func max(ns []int) int {
+	m := 0
+	for _, n := range ns {
+		if n > m {
+			m = n
+		}
+	}
+	return m
+}
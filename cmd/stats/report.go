@@ -0,0 +1,196 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+const (
+	// addedWeeks is number of weeks in the added words chart
+	addedWeeks = 12
+	// heatmapWeeks is number of weeks in the reviews heatmap
+	heatmapWeeks = 20
+	// forecastDays is number of days in the due reviews forecast
+	forecastDays = 30
+)
+
+// bucket is retention of reviews made after an interval since the previous one
+type bucket struct {
+	name       string
+	upTo       time.Duration
+	reviews    int
+	remembered int
+}
+
+// rate returns percentage of remembered reviews
+func (b bucket) rate() int {
+	if b.reviews == 0 {
+		return 0
+	}
+	return b.remembered * 100 / b.reviews
+}
+
+// report is all statistics of the store and review log
+type report struct {
+	now time.Time
+
+	total, suspended, unseen int
+	scores                   []int
+	decks                    map[string]int
+
+	// addedPerWeek are words added per week, the last one is the current week
+	addedPerWeek []int
+	// reviewsPerDay are reviews by day
+	reviewsPerDay map[time.Time]int
+	reviews       int
+	retention     []bucket
+	streak        int
+
+	sessions  int
+	timeSpent time.Duration
+	// accuracy is average accuracy of sessions
+	accuracy int
+	// forecast are due reviews per day, the first one is today with all overdue words
+	forecast []int
+}
+
+// makeReport computes statistics at the time
+func makeReport(ws store.Words, rs []store.Review, ss []store.Session, now time.Time) report {
+	r := report{
+		now:           now,
+		total:         len(ws),
+		scores:        make([]int, store.MaxScore+1),
+		decks:         map[string]int{},
+		addedPerWeek:  make([]int, addedWeeks),
+		reviewsPerDay: map[time.Time]int{},
+		reviews:       len(rs),
+		forecast:      make([]int, forecastDays),
+	}
+
+	today := dayOf(now)
+	thisWeek := weekOf(now)
+	for _, w := range ws {
+		if w.Score >= store.MinScore && w.Score <= store.MaxScore {
+			r.scores[w.Score]++
+		}
+		r.decks[w.Deck]++
+
+		if !w.AddedAt.IsZero() {
+			weeksAgo := daysBetween(weekOf(w.AddedAt), thisWeek) / 7
+			if weeksAgo >= 0 && weeksAgo < addedWeeks {
+				r.addedPerWeek[addedWeeks-1-weeksAgo]++
+			}
+		}
+
+		switch {
+		case w.Suspended:
+			r.suspended++
+		case w.LastSeenAt.IsZero():
+			r.unseen++
+		default:
+			d := daysBetween(today, dayOf(w.DueAt()))
+			if d < 0 {
+				d = 0
+			}
+			if d < forecastDays {
+				r.forecast[d]++
+			}
+		}
+	}
+
+	for _, rv := range rs {
+		r.reviewsPerDay[dayOf(rv.At)]++
+	}
+	r.retention = retention(ws, rs)
+	r.streak = streak(r.reviewsPerDay, today)
+
+	r.sessions = len(ss)
+	for _, s := range ss {
+		r.timeSpent += s.Duration()
+		r.accuracy += s.Accuracy()
+	}
+	if len(ss) > 0 {
+		r.accuracy /= len(ss)
+	}
+
+	return r
+}
+
+// retention splits reviews by interval since the previous review of the word (or since it was added)
+func retention(ws store.Words, rs []store.Review) []bucket {
+	bs := []bucket{
+		{name: "< 1 day", upTo: 24 * time.Hour},
+		{name: "1-3 days", upTo: 3 * 24 * time.Hour},
+		{name: "3-7 days", upTo: 7 * 24 * time.Hour},
+		{name: "1-2 weeks", upTo: 14 * 24 * time.Hour},
+		{name: "2-4 weeks", upTo: 28 * 24 * time.Hour},
+		{name: "> 4 weeks"},
+	}
+
+	prev := map[string]time.Time{}
+	for _, w := range ws {
+		if !w.AddedAt.IsZero() {
+			prev[w.Origin] = w.AddedAt
+		}
+	}
+
+	sorted := make([]store.Review, len(rs))
+	copy(sorted, rs)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].At.Before(sorted[j].At) })
+
+	for _, rv := range sorted {
+		last, ok := prev[rv.Origin]
+		prev[rv.Origin] = rv.At
+		if !ok {
+			continue
+		}
+
+		interval := rv.At.Sub(last)
+		for i := range bs {
+			if bs[i].upTo == 0 || interval < bs[i].upTo {
+				bs[i].reviews++
+				if rv.Remembered {
+					bs[i].remembered++
+				}
+				break
+			}
+		}
+	}
+
+	return bs
+}
+
+// streak counts days in a row with reviews, till today or yesterday
+func streak(perDay map[time.Time]int, today time.Time) int {
+	d := today
+	if perDay[d] == 0 {
+		d = d.AddDate(0, 0, -1)
+	}
+	n := 0
+	for perDay[d] > 0 {
+		n++
+		d = d.AddDate(0, 0, -1)
+	}
+	return n
+}
+
+// dayOf returns midnight of the day in local time
+func dayOf(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// weekOf returns midnight of Monday of the week
+func weekOf(t time.Time) time.Time {
+	d := dayOf(t)
+	return d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7))
+}
+
+// daysBetween counts calendar days from a to b, both are midnights
+func daysBetween(a, b time.Time) int {
+	// dates are compared by calendar to ignore DST shifts
+	return int(time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC).
+		Sub(time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)) / (24 * time.Hour))
+}
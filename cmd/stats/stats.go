@@ -0,0 +1,85 @@
+package stats
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/egregors/karten/pkg/store"
+)
+
+// WordFinder is store able to search words
+type WordFinder interface {
+	// Find returns all words matching the filter
+	Find(f store.Filter) (store.Words, error)
+}
+
+// ReviewReader is history of all answers
+type ReviewReader interface {
+	// All returns all reviews
+	All() ([]store.Review, error)
+}
+
+// SessionReader is history of all learning sessions
+type SessionReader interface {
+	// All returns all sessions
+	All() ([]store.Session, error)
+}
+
+// Srv is service to print statistics of the store
+type Srv struct {
+	Store    WordFinder
+	Log      ReviewReader
+	Sessions SessionReader
+
+	// TUI shows statistics as interactive dashboard
+	TUI bool
+
+	Out io.Writer
+}
+
+// NewSrv creates a new service to print statistics
+func NewSrv(s WordFinder, l ReviewReader, ss SessionReader, tui bool, out io.Writer) *Srv {
+	return &Srv{
+		Store:    s,
+		Log:      l,
+		Sessions: ss,
+		TUI:      tui,
+		Out:      out,
+	}
+}
+
+// Run prints statistics
+func (srv *Srv) Run() error {
+	ws, err := srv.Store.Find(nil)
+	if err != nil {
+		return err
+	}
+	rs, err := srv.Log.All()
+	if err != nil {
+		return err
+	}
+	ss, err := srv.Sessions.All()
+	if err != nil {
+		return err
+	}
+
+	r := makeReport(ws, rs, ss, time.Now())
+
+	if srv.TUI {
+		return tea.NewProgram(newDashboard(r), tea.WithAltScreen()).Start()
+	}
+
+	sections := []string{
+		r.overview(),
+		r.scoresChart(),
+		r.decksTable(),
+		r.addedChart(),
+		r.heatmap(),
+		r.retentionTable(),
+		r.forecastChart(),
+	}
+	_, err = io.WriteString(srv.Out, strings.Join(sections, "\n"))
+	return err
+}
@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+)
+
+var (
+	color       = termenv.EnvColorProfile().Color
+	activeStyle = termenv.Style{}.Foreground(color("150")).Styled
+	helpStyle   = termenv.Style{}.Foreground(color("241")).Styled
+)
+
+// tab is a page of the dashboard
+type tab struct {
+	title    string
+	sections func(r report) []string
+}
+
+var tabs = []tab{
+	{"overview", func(r report) []string { return []string{r.overview(), r.scoresChart(), r.decksTable()} }},
+	{"activity", func(r report) []string { return []string{r.heatmap(), r.addedChart()} }},
+	{"retention", func(r report) []string { return []string{r.retentionTable()} }},
+	{"forecast", func(r report) []string { return []string{r.forecastChart()} }},
+}
+
+// dashboard shows statistics split into tabs
+type dashboard struct {
+	Report report
+	Tab    int
+}
+
+func newDashboard(r report) dashboard {
+	return dashboard{Report: r}
+}
+
+func (m dashboard) Init() tea.Cmd {
+	return nil
+}
+
+func (m dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "right", "l", "tab":
+			m.Tab = (m.Tab + 1) % len(tabs)
+		case "left", "h", "shift+tab":
+			m.Tab = (m.Tab + len(tabs) - 1) % len(tabs)
+		}
+	}
+	return m, nil
+}
+
+func (m dashboard) View() string {
+	titles := make([]string, len(tabs))
+	for i, t := range tabs {
+		if i == m.Tab {
+			titles[i] = activeStyle("[" + t.title + "]")
+		} else {
+			titles[i] = " " + t.title + " "
+		}
+	}
+
+	frame := []string{
+		">>> Karten 🃏  " + strings.Join(titles, " ") + "\n",
+		strings.Join(tabs[m.Tab].sections(m.Report), "\n"),
+		helpStyle("  ←/→: switch tab • q: exit"),
+	}
+	return strings.Join(frame, "\n")
+}
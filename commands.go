@@ -0,0 +1,227 @@
+package main
+
+import (
+	"time"
+
+	"github.com/egregors/karten/cmd/add"
+	"github.com/egregors/karten/cmd/dict"
+	"github.com/egregors/karten/cmd/edit"
+	"github.com/egregors/karten/cmd/profiles"
+	"github.com/egregors/karten/pkg/store"
+)
+
+// FilterOpts is settings to choose words, shared by commands
+type FilterOpts struct {
+	Deck   string   `short:"d" long:"deck" description:"Only words from the deck"`
+	Tags   []string `short:"t" long:"tag" description:"Only words with the tag (could be repeated)"`
+	Filter string   `short:"f" long:"filter" description:"Filter expression, e.g. 'score<=2 and tag:verbs and not suspended'"`
+}
+
+// filter makes store.Filter from all the options
+func (o FilterOpts) filter() (store.Filter, error) {
+	f, err := store.ParseFilter(o.Filter)
+	if err != nil {
+		return nil, err
+	}
+	return store.And(store.ByDeck(o.Deck), store.ByTags(o.Tags...), f), nil
+}
+
+// WordArg is a single positional word argument
+type WordArg struct {
+	Word string `positional-arg-name:"word" description:"Word origin, quote it if it has spaces"`
+}
+
+// LearnCmd is settings of learn command
+type LearnCmd struct {
+	FilterOpts
+	Typed bool `long:"typed" description:"Type translations instead of self-grading"`
+	Cloze bool `long:"cloze" description:"Show words blanked in their example sentences, if they have ones"`
+}
+
+// AddCmd is settings of add command. Without words it runs interactive UI.
+type AddCmd struct {
+	Deck     string   `short:"d" long:"deck" description:"Deck to add new words into"`
+	Tags     []string `short:"t" long:"tag" description:"Tag new words (could be repeated, without UI only)"`
+	FromFile string   `long:"from-file" description:"Add words from the file, a word per line, '-' for stdin"`
+	Manual   []string `long:"manual" description:"Add the word with own translation: 'word=translation' (could be repeated)"`
+	Jobs     int      `short:"j" long:"jobs" default:"4" description:"Max number of concurrent lookups"`
+
+	Args struct {
+		Words []string `positional-arg-name:"word"`
+	} `positional-args:"yes"`
+}
+
+// batch checks if words should be added without UI
+func (c AddCmd) batch() bool {
+	return len(c.Args.Words) > 0 || len(c.Manual) > 0 || c.FromFile != ""
+}
+
+func (c AddCmd) batchOpts(timeout time.Duration) add.BatchOpts {
+	return add.BatchOpts{
+		Words:   c.Args.Words,
+		Manual:  c.Manual,
+		Path:    c.FromFile,
+		Deck:    c.Deck,
+		Tags:    c.Tags,
+		Workers: c.Jobs,
+		Timeout: timeout,
+	}
+}
+
+// LookupCmd is settings of lookup command
+type LookupCmd struct {
+	JSON bool `long:"json" description:"Print the card as JSON"`
+
+	Args WordArg `positional-args:"yes" required:"yes"`
+}
+
+// ListCmd is settings of list command
+type ListCmd struct {
+	FilterOpts
+}
+
+// BrowseCmd is settings of browse command
+type BrowseCmd struct {
+	FilterOpts
+}
+
+// ShowCmd is settings of show command
+type ShowCmd struct {
+	Args WordArg `positional-args:"yes" required:"yes"`
+}
+
+// EditCmd is settings of edit command
+type EditCmd struct {
+	Translation *string  `long:"translation" description:"New translations: 'go, walk; work, function'"`
+	Deck        *string  `long:"deck" description:"Move the word into the deck"`
+	Example     *string  `long:"example" description:"Set example sentence"`
+	Mnemonic    *string  `long:"mnemonic" description:"Set mnemonic"`
+	Notes       *string  `long:"notes" description:"Set notes"`
+	Tags        []string `short:"t" long:"tag" description:"Add the tag (could be repeated)"`
+	Untags      []string `long:"untag" description:"Remove the tag (could be repeated)"`
+	Suspend     bool     `long:"suspend" description:"Exclude the word from learning"`
+	Unsuspend   bool     `long:"unsuspend" description:"Bring the word back to learning"`
+	Reset       bool     `long:"reset" description:"Reset learning progress"`
+
+	Args WordArg `positional-args:"yes" required:"yes"`
+}
+
+func (c EditCmd) changes() edit.Changes {
+	return edit.Changes{
+		Translation: c.Translation,
+		Deck:        c.Deck,
+		Example:     c.Example,
+		Mnemonic:    c.Mnemonic,
+		Notes:       c.Notes,
+		AddTags:     c.Tags,
+		RemoveTags:  c.Untags,
+		Suspend:     c.Suspend,
+		Unsuspend:   c.Unsuspend,
+		Reset:       c.Reset,
+	}
+}
+
+// DeleteCmd is settings of delete command
+type DeleteCmd struct {
+	Args struct {
+		Words []string `positional-arg-name:"word" required:"1"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// ImportCmd is settings of import command
+type ImportCmd struct {
+	Format string   `long:"format" choice:"csv" choice:"tsv" choice:"json" description:"File format (by extension if empty, csv for stdin)"`
+	Deck   string   `short:"d" long:"deck" description:"Put imported words into the deck"`
+	Tags   []string `short:"t" long:"tag" description:"Tag imported words (could be repeated)"`
+
+	Args struct {
+		Path string `positional-arg-name:"file" description:"File to import, '-' for stdin"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// ExportCmd is settings of export command
+type ExportCmd struct {
+	FilterOpts
+	Format string `long:"format" choice:"csv" choice:"tsv" choice:"json" default:"csv" description:"File format"`
+	Output string `short:"o" long:"output" default:"-" description:"File to export into, '-' for stdout"`
+}
+
+// StatsCmd is settings of stats command
+type StatsCmd struct {
+	TUI bool `long:"tui" description:"Show interactive dashboard"`
+}
+
+// DoctorCmd is settings of doctor command
+type DoctorCmd struct {
+	Fix bool `long:"fix" description:"Repair found problems (files are backed up before)"`
+}
+
+// CacheCmd is settings of cache command
+type CacheCmd struct {
+	Stats struct{} `command:"stats" description:"Print number and size of cached lookups"`
+	Clear struct{} `command:"clear" description:"Remove all cached lookups"`
+}
+
+// DictCmd is settings of dict command
+type DictCmd struct {
+	Import struct {
+		Format string `long:"format" choice:"tsv" choice:"jsonl" description:"Dump format (by the file extension if empty)"`
+		Args   struct {
+			Path string `positional-arg-name:"file"`
+		} `positional-args:"yes" required:"yes"`
+	} `command:"import" description:"Import or refresh offline dictionary from dict.cc/FreeDict TSV or Wiktionary JSONL dump"`
+
+	Search struct {
+		Limit int `short:"n" long:"limit" default:"20" description:"Max number of words"`
+		Args  struct {
+			Prefix string `positional-arg-name:"prefix"`
+		} `positional-args:"yes" required:"yes"`
+	} `command:"search" description:"Print words of offline dictionary starting with the prefix"`
+}
+
+// opts returns arguments of the action
+func (c DictCmd) opts() dict.Opts {
+	return dict.Opts{
+		Path:   c.Import.Args.Path,
+		Format: c.Import.Format,
+		Prefix: c.Search.Args.Prefix,
+		Limit:  c.Search.Limit,
+	}
+}
+
+// ProfileCmd is settings of profile command
+type ProfileCmd struct {
+	List struct{} `command:"list" description:"Print all profiles, current one is marked"`
+
+	Create struct {
+		Args struct {
+			Name string `positional-arg-name:"name"`
+		} `positional-args:"yes" required:"yes"`
+	} `command:"create" description:"Create a new empty profile"`
+
+	Copy struct {
+		Args struct {
+			Src string `positional-arg-name:"src"`
+			Dst string `positional-arg-name:"dst"`
+		} `positional-args:"yes" required:"yes"`
+	} `command:"copy" description:"Create a new profile with all words and progress of another one"`
+
+	Delete struct {
+		Args struct {
+			Name string `positional-arg-name:"name"`
+		} `positional-args:"yes" required:"yes"`
+	} `command:"delete" description:"Delete the profile with all its data"`
+}
+
+// names returns profile names arguments of the action
+func (c ProfileCmd) names(action string) []string {
+	switch action {
+	case profiles.ActionCreate:
+		return []string{c.Create.Args.Name}
+	case profiles.ActionCopy:
+		return []string{c.Copy.Args.Src, c.Copy.Args.Dst}
+	case profiles.ActionDelete:
+		return []string{c.Delete.Args.Name}
+	}
+	return nil
+}
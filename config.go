@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jessevdk/go-flags"
+)
+
+// Settings are options could be set in the config file, as well as by cli args or ENV
+type Settings struct {
+	Store          string        `long:"store" env:"KARTEN_STORE" ini-name:"store" description:"Words file (words.csv in the profile dir if empty)"`
+	SessionSize    int           `long:"session-size" env:"KARTEN_SESSION_SIZE" ini-name:"session-size" default:"20" description:"Number of words for one learning session"`
+	Providers      string        `long:"providers" env:"KARTEN_PROVIDERS" ini-name:"providers" default:"verbformen" description:"Comma separated providers to look up words in, in order of fallback: verbformen, offline"`
+	Merge          bool          `long:"merge-providers" env:"KARTEN_MERGE_PROVIDERS" ini-name:"merge-providers" description:"Fill fields missed by a provider (e.g. forms) from the next ones"`
+	CacheDir       string        `long:"cache-dir" env:"KARTEN_CACHE_DIR" ini-name:"cache-dir" description:"Dir to cache lookups in (cache in the data dir if empty)"`
+	CacheTTL       time.Duration `long:"cache-ttl" env:"KARTEN_CACHE_TTL" ini-name:"cache-ttl" default:"720h" description:"How long to keep cached lookups, 0 disables the cache"`
+	CacheSize      int           `long:"cache-size" env:"KARTEN_CACHE_SIZE" ini-name:"cache-size" default:"10000" description:"Max number of cached lookups"`
+	DictDir        string        `long:"dict-dir" env:"KARTEN_DICT_DIR" ini-name:"dict-dir" description:"Dir of offline dictionary (dict in the data dir if empty)"`
+	Languages      string        `long:"language" env:"KARTEN_LANGUAGE" ini-name:"language" default:"en" description:"Preferred languages of translations, comma separated, e.g. ru,en"`
+	VerbFormenURL  string        `long:"verbformen-url" env:"KARTEN_VERBFORMEN_URL" ini-name:"verbformen-url" default:"https://www.verbformen.com/?w=" description:"VerbFormen search URL"`
+	Conjugation    bool          `long:"conjugation" env:"KARTEN_CONJUGATION" ini-name:"conjugation" description:"Fetch conjugation tables of verbs from VerbFormen"`
+	ConjugationURL string        `long:"conjugation-url" env:"KARTEN_CONJUGATION_URL" ini-name:"conjugation-url" default:"https://www.verbformen.com/conjugation/?w=" description:"VerbFormen conjugation URL"`
+	LookupTimeout  time.Duration `long:"lookup-timeout" env:"KARTEN_LOOKUP_TIMEOUT" ini-name:"lookup-timeout" default:"10s" description:"Max time to look up a word"`
+	LookupRetries  int           `long:"lookup-retries" env:"KARTEN_LOOKUP_RETRIES" ini-name:"lookup-retries" default:"2" description:"Number of retries of a lookup failed because the provider is busy"`
+
+	Colors ColorSettings `group:"Colors" namespace:"color" env-namespace:"KARTEN_COLOR"`
+	Keys   KeySettings   `group:"Keys" namespace:"key" env-namespace:"KARTEN_KEY"`
+}
+
+// ColorSettings are colors of learn UI: ANSI codes ("0"-"255") or hex ("#ff0000")
+type ColorSettings struct {
+	Word   string `long:"word" env:"WORD" ini-name:"word" default:"150" description:"Word color"`
+	Help   string `long:"help" env:"HELP" ini-name:"help" default:"241" description:"Help and hints color"`
+	Good   string `long:"good" env:"GOOD" ini-name:"good" default:"46" description:"Remembered words color"`
+	Bad    string `long:"bad" env:"BAD" ini-name:"bad" default:"69" description:"Forgotten words color"`
+	Finish string `long:"finish" env:"FINISH" ini-name:"finish" default:"10" description:"Session summary color"`
+}
+
+// KeySettings are key bindings of learn UI, like "up", "ctrl+k", "space" or "q"
+type KeySettings struct {
+	Know   string `long:"know" env:"KNOW" ini-name:"know" default:"down" description:"I know the word"`
+	Forget string `long:"forget" env:"FORGET" ini-name:"forget" default:"up" description:"I don't remember the word"`
+	Flip   string `long:"flip" env:"FLIP" ini-name:"flip" default:"space" description:"Flip the card"`
+	Edit   string `long:"edit" env:"EDIT" ini-name:"edit" default:"e" description:"Edit translation and notes of the word"`
+	Undo   string `long:"undo" env:"UNDO" ini-name:"undo" default:"u" description:"Undo the last answer"`
+	Quit   string `long:"quit" env:"QUIT" ini-name:"quit" default:"q" description:"Quit"`
+}
+
+// languages returns preferred languages of translations
+func (s Settings) languages() []string {
+	var ls []string
+	for _, l := range strings.Split(s.Languages, ",") {
+		if l = strings.ToLower(strings.TrimSpace(l)); l != "" {
+			ls = append(ls, l)
+		}
+	}
+	return ls
+}
+
+// ConfigCmd is settings of config command
+type ConfigCmd struct{}
+
+// configPaths returns paths of config files in order of loading: global one is
+// $XDG_CONFIG_HOME/karten/config.ini if XDG_CONFIG_HOME is set, data-dir/config.ini otherwise,
+// and the profile own config, which overrides global settings.
+func configPaths(dataDir, profileDir string) []string {
+	global := filepath.Join(dataDir, "config.ini")
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		global = filepath.Join(xdg, "karten", "config.ini")
+	}
+
+	local := filepath.Join(profileDir, "config.ini")
+	if local == global {
+		return []string{global}
+	}
+	return []string{global, local}
+}
+
+// settingsParser makes parser of config file for the settings
+func settingsParser(s *Settings) (*flags.Parser, error) {
+	p := flags.NewNamedParser("karten", flags.None)
+	if _, err := p.AddGroup("Settings", "", s); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// loadConfig reads the config file (if it exists) and uses its values as defaults of the parser
+// options, so the precedence is: cli args > ENV > config file > defaults.
+func loadConfig(p *flags.Parser, path string) error {
+	var file Settings
+	fp, err := settingsParser(&file)
+	if err != nil {
+		return err
+	}
+
+	if err := flags.NewIniParser(fp).ParseFile(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("can't read config %s: %w", path, err)
+	}
+
+	for _, g := range fp.Groups() {
+		for _, opt := range allOptions(g) {
+			if !opt.IsSet() {
+				continue
+			}
+			target := p.FindOptionByLongName(opt.LongNameWithNamespace())
+			if target == nil {
+				continue
+			}
+			target.Default = []string{fmt.Sprint(opt.Value())}
+		}
+	}
+
+	return nil
+}
+
+// allOptions returns options of the group and all its subgroups
+func allOptions(g *flags.Group) []*flags.Option {
+	opts := g.Options()
+	for _, sub := range g.Groups() {
+		opts = append(opts, allOptions(sub)...)
+	}
+	return opts
+}
+
+// printConfig prints effective settings in the config file format
+func printConfig(w io.Writer, s *Settings, paths []string) error {
+	p, err := settingsParser(s)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "; effective settings, config files: %s\n\n", strings.Join(paths, ", ")); err != nil {
+		return err
+	}
+	flags.NewIniParser(p).Write(w, flags.IniIncludeDefaults|flags.IniIncludeComments)
+	return nil
+}
@@ -4,9 +4,25 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/egregors/karten/cmd/add"
+	"github.com/egregors/karten/cmd/browse"
+	"github.com/egregors/karten/cmd/cache"
+	"github.com/egregors/karten/cmd/dict"
+	"github.com/egregors/karten/cmd/doctor"
+	"github.com/egregors/karten/cmd/edit"
+	"github.com/egregors/karten/cmd/exporter"
+	"github.com/egregors/karten/cmd/importer"
 	"github.com/egregors/karten/cmd/learn"
+	"github.com/egregors/karten/cmd/list"
+	"github.com/egregors/karten/cmd/lookup"
+	"github.com/egregors/karten/cmd/profiles"
+	"github.com/egregors/karten/cmd/remove"
+	"github.com/egregors/karten/cmd/show"
+	"github.com/egregors/karten/cmd/stats"
+	"github.com/egregors/karten/pkg/profile"
 	"github.com/egregors/karten/pkg/provider"
 	"github.com/egregors/karten/pkg/store"
 	"github.com/jessevdk/go-flags"
@@ -19,13 +35,66 @@ type Server interface {
 
 // Opts is App settings (from cli args or ENV)
 type Opts struct {
-	Add bool `short:"a" long:"add" description:"Run add-mode to add new word in your collection"`
 	Dbg bool `long:"dbg" env:"DEBUG" description:"Debug mode"`
+
+	Location Location `group:"Location"`
+	Settings Settings `group:"Settings"`
+
+	Learn   LearnCmd   `command:"learn" description:"Learn words (default command)"`
+	Add     AddCmd     `command:"add" description:"Add new words into your collection"`
+	Lookup  LookupCmd  `command:"lookup" description:"Print dictionary card of the word without saving it"`
+	List    ListCmd    `command:"list" description:"Print words from your collection"`
+	Browse  BrowseCmd  `command:"browse" description:"Browse, search and edit words"`
+	Show    ShowCmd    `command:"show" description:"Print everything about the word"`
+	Edit    EditCmd    `command:"edit" description:"Change the word"`
+	Delete  DeleteCmd  `command:"delete" description:"Delete words from your collection"`
+	Import  ImportCmd  `command:"import" description:"Import words from a file"`
+	Export  ExportCmd  `command:"export" description:"Export words into a file"`
+	Stats   StatsCmd   `command:"stats" description:"Print statistics of your collection"`
+	Doctor  DoctorCmd  `command:"doctor" description:"Check the store integrity and repair it"`
+	Config  ConfigCmd  `command:"config" description:"Print effective settings"`
+	Profile ProfileCmd `command:"profile" description:"Manage profiles"`
+	Cache   CacheCmd   `command:"cache" description:"Manage cache of dictionary lookups"`
+	Dict    DictCmd    `command:"dict" description:"Manage offline dictionary"`
+}
+
+// notFoundTTL is how long words unknown to a provider are cached, they could be added there soon
+const notFoundTTL = 24 * time.Hour
+
+// Location is where all the data is kept, it could be set by cli args or ENV only
+type Location struct {
+	DataDir string `long:"data-dir" env:"KARTEN_DATA_DIR" description:"Data directory (~/.karten if empty)"`
+	Profile string `long:"profile" env:"KARTEN_PROFILE" default:"default" description:"Profile name"`
 }
 
 func main() {
 	var opts Opts
 	p := flags.NewParser(&opts, flags.PrintErrors|flags.PassDoubleDash|flags.HelpFlag)
+	// karten without a command is learn
+	p.SubcommandsOptional = true
+
+	// data dir and profile are needed to find config files, before the rest is parsed
+	loc := parseLocation()
+	root, err := dataDir(loc.DataDir)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	profs := profile.Profiles{DataDir: root}
+	dir, err := profs.Dir(loc.Profile)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	cfgs := configPaths(root, dir)
+	for _, cfg := range cfgs {
+		if err := loadConfig(p, cfg); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+
 	if _, err := p.Parse(); err != nil {
 		if err.(*flags.Error).Type != flags.ErrHelp {
 			fmt.Printf("cli error: %v", err)
@@ -33,30 +102,78 @@ func main() {
 		os.Exit(2)
 	}
 
-	storage, err := makeStorage()
-	if err != nil {
-		fmt.Printf("can't make a storage: %s", err.Error())
+	cmd := "learn"
+	if p.Active != nil {
+		cmd = p.Active.Name
 	}
 
-	var srv Server
+	if err := resolveDirs(&opts.Settings, root); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
 
-	if opts.Add { // run add-mode
-		srv = add.NewSrv(
-			storage,
-			provider.VerbFormen{URL: "https://www.verbformen.com/?w="},
-			opts.Dbg,
-		)
-	} else { // learn mode
-		srv, err = learn.NewSrv(
-			storage,
-			opts.Dbg,
-		)
+	switch cmd {
+	case "config":
+		if err := printConfig(os.Stdout, &opts.Settings, cfgs); err != nil {
+			fmt.Println("ERR: ", err)
+			os.Exit(1)
+		}
+		return
 
-		if err != nil {
-			fmt.Printf("can't make server: %s\n", err)
+	case "profile":
+		action := p.Active.Active.Name
+		srv := profiles.NewSrv(profs, loc.Profile, action, opts.Profile.names(action), os.Stdout)
+		if err := srv.Run(); err != nil {
+			fmt.Println("ERR: ", err)
+			os.Exit(1)
+		}
+		return
+
+	case "dict":
+		srv := dict.NewSrv(&provider.Offline{Dir: opts.Settings.DictDir}, p.Active.Active.Name, opts.Dict.opts(), os.Stdout)
+		if err := srv.Run(); err != nil {
+			fmt.Println("ERR: ", err)
 			os.Exit(1)
 		}
+		return
 
+	case "cache":
+		srv := cache.NewSrv(makeCache(opts.Settings), p.Active.Active.Name, os.Stdout)
+		if err := srv.Run(); err != nil {
+			fmt.Println("ERR: ", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if !profs.Exists(loc.Profile) {
+		fmt.Printf("profile %q does not exist, create it by `karten profile create %s`\n", loc.Profile, loc.Profile)
+		os.Exit(1)
+	}
+
+	// doctor checks the file as is, migration would reset malformed values
+	storage, err := makeStorage(opts.Settings.Store, dir, cmd != "doctor")
+	if err != nil {
+		fmt.Printf("can't make a storage: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	reviews, err := makeReviewLog(storage)
+	if err != nil {
+		fmt.Printf("can't make a review log: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	sessions, err := store.NewSessionLog(filepath.Join(filepath.Dir(storage.Path), "sessions.csv"))
+	if err != nil {
+		fmt.Printf("can't make a session log: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	srv, err := makeServer(cmd, &opts, storage, reviews, sessions)
+	if err != nil {
+		fmt.Printf("can't make server: %s\n", err)
+		os.Exit(1)
 	}
 
 	if err := srv.Run(); err != nil {
@@ -65,21 +182,219 @@ func main() {
 	}
 }
 
-func makeStorage() (*store.CSV, error) {
+// makeServer makes a service for the command
+func makeServer(cmd string, opts *Opts, storage *store.CSV, reviews *store.ReviewLog, sessions *store.SessionLog) (Server, error) {
+	switch cmd {
+	case "add":
+		p, err := makeProvider(opts.Settings)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Add.batch() {
+			return add.NewBatchSrv(storage, p, opts.Add.batchOpts(opts.Settings.LookupTimeout), os.Stdin, os.Stdout), nil
+		}
+		return add.NewSrv(storage, p, opts.Add.Deck, opts.Settings.LookupTimeout, opts.Dbg), nil
+
+	case "lookup":
+		p, err := makeProvider(opts.Settings)
+		if err != nil {
+			return nil, err
+		}
+		return lookup.NewSrv(p, storage, opts.Lookup.Args.Word, opts.Settings.LookupTimeout, opts.Lookup.JSON, os.Stdout), nil
+
+	case "list":
+		f, err := opts.List.filter()
+		if err != nil {
+			return nil, err
+		}
+		return list.NewSrv(storage, f, os.Stdout), nil
+
+	case "browse":
+		f, err := opts.Browse.filter()
+		if err != nil {
+			return nil, err
+		}
+		return browse.NewSrv(storage, f, opts.Settings.languages(), opts.Dbg)
+
+	case "show":
+		return show.NewSrv(storage, opts.Show.Args.Word, os.Stdout), nil
+
+	case "edit":
+		return edit.NewSrv(storage, opts.Edit.Args.Word, opts.Edit.changes(), os.Stdout), nil
+
+	case "delete":
+		return remove.NewSrv(storage, opts.Delete.Args.Words, os.Stdout), nil
+
+	case "import":
+		return importer.NewSrv(
+			storage,
+			opts.Import.Args.Path,
+			opts.Import.Format,
+			opts.Import.Deck,
+			opts.Import.Tags,
+			os.Stdin,
+			os.Stdout,
+		), nil
+
+	case "export":
+		f, err := opts.Export.filter()
+		if err != nil {
+			return nil, err
+		}
+		return exporter.NewSrv(storage, f, opts.Export.Output, opts.Export.Format, os.Stdout), nil
+
+	case "stats":
+		return stats.NewSrv(storage, reviews, sessions, opts.Stats.TUI, os.Stdout), nil
+
+	case "doctor":
+		return doctor.NewSrv(storage, reviews, opts.Doctor.Fix, os.Stdout), nil
+
+	default: // learn
+		f, err := opts.Learn.filter()
+		if err != nil {
+			return nil, err
+		}
+		c, k := opts.Settings.Colors, opts.Settings.Keys
+		return learn.NewSrv(storage, reviews, sessions, learn.Opts{
+			Filter:      store.And(store.NotSuspended, f),
+			SessionSize: opts.Settings.SessionSize,
+			Typed:       opts.Learn.Typed,
+			Cloze:       opts.Learn.Cloze,
+			Colors:      learn.Colors{Word: c.Word, Help: c.Help, Good: c.Good, Bad: c.Bad, Finish: c.Finish},
+			Keys:        learn.Keys{Know: k.Know, Forget: k.Forget, Flip: k.Flip, Edit: k.Edit, Undo: k.Undo, Quit: k.Quit},
+			Languages:   opts.Settings.languages(),
+			Dbg:         opts.Dbg,
+		})
+	}
+}
+
+// parseLocation parses data dir and profile only, all other args are ignored
+func parseLocation() Location {
+	var loc Location
+	p := flags.NewNamedParser("karten", flags.IgnoreUnknown)
+	if _, err := p.AddGroup("Location", "", &loc); err != nil {
+		return loc
+	}
+	// errors are reported by the main parser
+	_, _ = p.Parse()
+	return loc
+}
+
+// dataDir returns the data dir, ~/.karten by default
+func dataDir(dir string) (string, error) {
+	if dir == "" {
+		dir = "~/.karten"
+	}
+	return expandHome(dir)
+}
+
+// expandHome replaces leading ~ with the user home dir
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return nil, fmt.Errorf("can't get home dir: %w", err)
+		return "", fmt.Errorf("can't get home dir: %w", err)
 	}
-	path := filepath.Join(home, ".karten", "words.csv")
+	return filepath.Join(home, path[1:]), nil
+}
 
+// makeStorage makes words store by path, or in the profile dir if path is empty
+func makeStorage(path, profileDir string, migrate bool) (*store.CSV, error) {
+	if path == "" {
+		path = filepath.Join(profileDir, "words.csv")
+	}
+	path, err := expandHome(path)
 	if err != nil {
-		return nil, fmt.Errorf("can't get CSV path: %w", err)
+		return nil, err
 	}
 
-	storage, err := store.NewCSV(path)
+	open := store.NewCSV
+	if !migrate {
+		open = store.OpenCSV
+	}
+	storage, err := open(path)
 	if err != nil {
 		return nil, fmt.Errorf("can't create store: %w", err)
 	}
 
 	return storage, nil
 }
+
+// makeReviewLog makes review log next to the words store
+func makeReviewLog(s *store.CSV) (*store.ReviewLog, error) {
+	l, err := store.NewReviewLog(filepath.Join(filepath.Dir(s.Path), "reviews.csv"))
+	if err != nil {
+		return nil, fmt.Errorf("can't create review log: %w", err)
+	}
+
+	return l, nil
+}
+
+// makeProvider makes chain of dictionary providers by the settings, each one is cached
+func makeProvider(s Settings) (*provider.Chain, error) {
+	chain := &provider.Chain{Merge: s.Merge, Languages: s.languages()}
+	dc := makeCache(s)
+	for _, name := range strings.Split(s.Providers, ",") {
+		var p provider.Provider
+		switch strings.TrimSpace(name) {
+		case "":
+			continue
+		case "verbformen":
+			vf := provider.VerbFormen{
+				URL:       s.VerbFormenURL,
+				Retries:   s.LookupRetries,
+				Languages: s.languages(),
+			}
+			if s.Conjugation {
+				vf.ConjugationURL = s.ConjugationURL
+			}
+			p = vf
+		case "offline":
+			// it's fast enough without cache
+			chain.Providers = append(chain.Providers, &provider.Offline{Dir: s.DictDir})
+			continue
+		default:
+			return nil, fmt.Errorf("unknown provider %q", name)
+		}
+
+		if s.CacheTTL > 0 {
+			p = dc.Wrap(p)
+		}
+		chain.Providers = append(chain.Providers, p)
+	}
+	return chain, nil
+}
+
+// resolveDirs sets dirs of the cache and offline dictionary in the data dir, if they are not set
+func resolveDirs(s *Settings, root string) error {
+	dirs := []struct {
+		path *string
+		name string
+	}{
+		{&s.CacheDir, "cache"},
+		{&s.DictDir, "dict"},
+	}
+	for _, d := range dirs {
+		if *d.path == "" {
+			*d.path = filepath.Join(root, d.name)
+		}
+		p, err := expandHome(*d.path)
+		if err != nil {
+			return err
+		}
+		*d.path = p
+	}
+	return nil
+}
+
+// makeCache makes cache of dictionary lookups by the settings
+func makeCache(s Settings) provider.DiskCache {
+	return provider.DiskCache{
+		Dir:         s.CacheDir,
+		TTL:         s.CacheTTL,
+		NotFoundTTL: notFoundTTL,
+		MaxEntries:  s.CacheSize,
+	}
+}
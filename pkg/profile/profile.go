@@ -0,0 +1,141 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Default is the profile which lives in the data dir root, it's always exist
+const Default = "default"
+
+const profilesDir = "profiles"
+
+var (
+	// ErrNotFound means there is no profile with such name
+	ErrNotFound = errors.New("profile not found")
+	// ErrExists means the profile with such name already exists
+	ErrExists = errors.New("profile already exists")
+
+	validName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// Profiles are separate sets of words, reviews and config in the data dir.
+// Default profile lives in the DataDir root, any other one in DataDir/profiles/<name>.
+type Profiles struct {
+	DataDir string
+}
+
+// Dir returns directory of the profile
+func (p Profiles) Dir(name string) (string, error) {
+	if name == Default {
+		return p.DataDir, nil
+	}
+	if !validName.MatchString(name) {
+		return "", fmt.Errorf("bad profile name %q, use latin letters, digits, '-' and '_'", name)
+	}
+	return filepath.Join(p.DataDir, profilesDir, name), nil
+}
+
+// Exists checks if the profile exists
+func (p Profiles) Exists(name string) bool {
+	if name == Default {
+		return true
+	}
+	dir, err := p.Dir(name)
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir()
+}
+
+// List returns names of all profiles, default is the first one
+func (p Profiles) List() ([]string, error) {
+	names := []string{Default}
+
+	es, err := os.ReadDir(filepath.Join(p.DataDir, profilesDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return names, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var others []string
+	for _, e := range es {
+		if e.IsDir() && validName.MatchString(e.Name()) && e.Name() != Default {
+			others = append(others, e.Name())
+		}
+	}
+	sort.Strings(others)
+
+	return append(names, others...), nil
+}
+
+// Create makes a new empty profile
+func (p Profiles) Create(name string) error {
+	dir, err := p.Dir(name)
+	if err != nil {
+		return err
+	}
+	if p.Exists(name) {
+		return fmt.Errorf("%w: %s", ErrExists, name)
+	}
+	return os.MkdirAll(dir, 0o700)
+}
+
+// Copy makes a new profile dst with all words, reviews and config of src. Backups are not copied.
+func (p Profiles) Copy(src, dst string) error {
+	if !p.Exists(src) {
+		return fmt.Errorf("%w: %s", ErrNotFound, src)
+	}
+	srcDir, err := p.Dir(src)
+	if err != nil {
+		return err
+	}
+	if err := p.Create(dst); err != nil {
+		return err
+	}
+	dstDir, err := p.Dir(dst)
+	if err != nil {
+		return err
+	}
+
+	es, err := os.ReadDir(srcDir)
+	if err != nil {
+		return err
+	}
+	for _, e := range es {
+		if !e.Type().IsRegular() || strings.HasSuffix(e.Name(), ".bak") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(srcDir, e.Name()))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dstDir, e.Name()), data, 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete removes the profile with all its data. Default profile could not be deleted.
+func (p Profiles) Delete(name string) error {
+	if name == Default {
+		return errors.New("default profile could not be deleted")
+	}
+	if !p.Exists(name) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	dir, err := p.Dir(name)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
@@ -0,0 +1,307 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec // it's just file names
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by providers which don't know the word
+var ErrNotFound = errors.New("can't find the word")
+
+const (
+	// pruneInterval is how often put prunes the cache, pruning reads all entries
+	pruneInterval = time.Hour
+	// pruneStamp is file in the cache dir modified on every pruning
+	pruneStamp = ".pruned"
+)
+
+// Fingerprinter is a Provider with settings changing its cards, e.g. languages of translations.
+// Its cards are cached by the fingerprint too, so they are looked up again once the settings change.
+type Fingerprinter interface {
+	Fingerprint() string
+}
+
+// DiskCache keeps cards found by providers on disk, a file per query, in a dir per provider.
+// Words providers don't know are cached too, for NotFoundTTL.
+type DiskCache struct {
+	Dir string
+	// TTL is how long found cards are kept
+	TTL time.Duration
+	// NotFoundTTL is how long not found words are kept
+	NotFoundTTL time.Duration
+	// MaxEntries is max number of cached queries, the oldest ones are removed first. No limit if zero.
+	MaxEntries int
+}
+
+// CacheStats are numbers of cached queries
+type CacheStats struct {
+	Entries, NotFound, Expired int
+	// Size is total size of cached files in bytes
+	Size           int64
+	Oldest, Newest time.Time
+}
+
+// entry is a cached lookup result
+type entry struct {
+	Query string    `json:"query"`
+	At    time.Time `json:"at"`
+	// Card is nil if the word is not found
+	Card *Card `json:"card,omitempty"`
+	// More are other cards matching the query
+	More []*Card `json:"more,omitempty"`
+}
+
+// Wrap returns the provider looking up in the cache first
+func (c DiskCache) Wrap(p Provider) Provider {
+	cp := cached{Provider: p, cache: c}
+	if f, ok := p.(Fingerprinter); ok {
+		cp.fingerprint = f.Fingerprint()
+	}
+	return cp
+}
+
+// Stats counts cached queries
+func (c DiskCache) Stats() (CacheStats, error) {
+	var st CacheStats
+	now := time.Now()
+	err := c.walk(func(path string, info fs.FileInfo) {
+		e, err := readEntry(path)
+		if err != nil {
+			return
+		}
+		st.Entries++
+		st.Size += info.Size()
+		if e.Card == nil {
+			st.NotFound++
+		}
+		if c.expired(e, now) {
+			st.Expired++
+		}
+		if st.Oldest.IsZero() || e.At.Before(st.Oldest) {
+			st.Oldest = e.At
+		}
+		if e.At.After(st.Newest) {
+			st.Newest = e.At
+		}
+	})
+	return st, err
+}
+
+// Clear removes all cached queries. Only entries and empty dirs of providers are removed,
+// so a misconfigured Dir (e.g. home) loses nothing else.
+func (c DiskCache) Clear() error {
+	var paths []string
+	if err := c.walk(func(path string, _ fs.FileInfo) { paths = append(paths, path) }); err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	dirs, err := filepath.Glob(filepath.Join(c.Dir, "*"))
+	if err != nil {
+		return err
+	}
+	for _, d := range dirs {
+		if des, err := os.ReadDir(d); err == nil && len(des) == 0 {
+			_ = os.Remove(d)
+		}
+	}
+	return nil
+}
+
+func (c DiskCache) get(provider, fingerprint, query string, now time.Time) (entry, bool) {
+	e, err := readEntry(c.path(provider, fingerprint, query))
+	if err != nil || c.expired(e, now) {
+		return entry{}, false
+	}
+	return e, true
+}
+
+func (c DiskCache) put(provider, fingerprint string, e entry) error {
+	path := c.path(provider, fingerprint, e.Query)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	// written to a temp file first, so concurrent lookups never read a half-written entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	if !c.pruneDue(time.Now()) {
+		return nil
+	}
+	return c.prune()
+}
+
+// pruneDue checks if the cache wasn't pruned for pruneInterval, by any process, and marks it pruned now
+func (c DiskCache) pruneDue(now time.Time) bool {
+	if c.MaxEntries <= 0 {
+		return false
+	}
+	stamp := filepath.Join(c.Dir, pruneStamp)
+	if info, err := os.Stat(stamp); err == nil && now.Sub(info.ModTime()) < pruneInterval {
+		return false
+	}
+	if err := os.Chtimes(stamp, now, now); errors.Is(err, os.ErrNotExist) {
+		_ = os.WriteFile(stamp, nil, 0o600)
+	}
+	return true
+}
+
+// prune removes the oldest entries over MaxEntries
+func (c DiskCache) prune() error {
+	if c.MaxEntries <= 0 {
+		return nil
+	}
+
+	type file struct {
+		path string
+		mod  time.Time
+	}
+	var files []file
+	err := c.walk(func(path string, info fs.FileInfo) {
+		files = append(files, file{path, info.ModTime()})
+	})
+	if err != nil || len(files) <= c.MaxEntries {
+		return err
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].mod.Before(files[j].mod) })
+	for _, f := range files[:len(files)-c.MaxEntries] {
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// walk calls fn for every cached entry file, in dirs of providers only
+func (c DiskCache) walk(fn func(path string, info fs.FileInfo)) error {
+	paths, err := filepath.Glob(filepath.Join(c.Dir, "*", "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			// entries could be removed by concurrent pruning
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+		if info.Mode().IsRegular() {
+			fn(path, info)
+		}
+	}
+	return nil
+}
+
+func (c DiskCache) expired(e entry, now time.Time) bool {
+	ttl := c.TTL
+	if e.Card == nil {
+		ttl = c.NotFoundTTL
+	}
+	return now.Sub(e.At) > ttl
+}
+
+// path is the entry file of the query, the key is the query with the fingerprint of provider settings if any
+func (c DiskCache) path(provider, fingerprint, query string) string {
+	key := normalize(query)
+	if fingerprint != "" {
+		key = fingerprint + "\n" + key
+	}
+	sum := sha1.Sum([]byte(key)) //nolint:gosec // it's just file names
+	return filepath.Join(c.Dir, provider, hex.EncodeToString(sum[:])+".json")
+}
+
+// normalize makes the same key for queries differ in case or spaces only
+func normalize(query string) string {
+	return strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
+
+func readEntry(path string) (entry, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return entry{}, err
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return entry{}, err
+	}
+	return e, nil
+}
+
+// cached is Provider decorator looking up in the DiskCache first
+type cached struct {
+	Provider
+	cache DiskCache
+	// fingerprint is of the provider settings, empty if it has none
+	fingerprint string
+}
+
+// Lookup returns the best cached Card of the word
+func (c cached) Lookup(ctx context.Context, s string) (*Card, error) {
+	cards, err := c.LookupAll(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	return cards[0], nil
+}
+
+// LookupAll returns cached cards of the word, or looks them up in the provider and caches the result.
+// Only found cards and ErrNotFound are cached, other errors (e.g. network ones) are not.
+func (c cached) LookupAll(ctx context.Context, s string) ([]*Card, error) {
+	now := time.Now()
+	if e, ok := c.cache.get(c.Name(), c.fingerprint, s, now); ok {
+		if e.Card == nil {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, s)
+		}
+		return append([]*Card{e.Card}, e.More...), nil
+	}
+
+	cards, err := lookupAll(ctx, c.Provider, s)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	e := entry{Query: s, At: now}
+	if len(cards) > 0 {
+		e.Card, e.More = cards[0], cards[1:]
+	}
+	// the word is looked up anyway, failed cache is not a reason to fail the lookup
+	_ = c.cache.put(c.Name(), c.fingerprint, e)
+
+	return cards, err
+}
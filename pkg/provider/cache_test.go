@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+// counter is a Provider counting lookups, with settings changing its cards
+type counter struct {
+	lang  string
+	calls *int
+}
+
+func (c counter) Name() string { return "counter" }
+
+func (c counter) Fingerprint() string { return "lang=" + c.lang }
+
+func (c counter) Lookup(_ context.Context, s string) (*Card, error) {
+	*c.calls++
+	if s == "xyz" {
+		return nil, ErrNotFound
+	}
+	return &Card{Origin: []string{s}, Lang: c.lang, Senses: store.ParseSenses("go")}, nil
+}
+
+func TestCached_Lookup(t *testing.T) {
+	dc := DiskCache{Dir: t.TempDir(), TTL: time.Hour, NotFoundTTL: time.Hour}
+	calls := 0
+	en := dc.Wrap(counter{lang: "en", calls: &calls})
+
+	for i := 0; i < 2; i++ {
+		card, err := en.Lookup(context.Background(), "gehen")
+		if err != nil || card.Word() != "gehen" {
+			t.Fatalf("got %v, %v", card, err)
+		}
+		if _, err := en.Lookup(context.Background(), "xyz"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("got %v, want %v", err, ErrNotFound)
+		}
+	}
+	// queries differ in case or spaces only are the same
+	if _, err := en.Lookup(context.Background(), " Gehen "); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("got %d lookups, want 2", calls)
+	}
+
+	// other settings have their own cards
+	ru := dc.Wrap(counter{lang: "ru", calls: &calls})
+	card, err := ru.Lookup(context.Background(), "gehen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Lang != "ru" || calls != 3 {
+		t.Errorf("got cached %q card, %d lookups", card.Lang, calls)
+	}
+}
+
+func TestVerbFormen_Fingerprint(t *testing.T) {
+	v := VerbFormen{URL: "https://www.verbformen.com/?w=", Languages: []string{"ru", "en"}}
+	tbl := []VerbFormen{
+		{URL: v.URL},
+		{URL: v.URL, Languages: []string{"en", "ru"}},
+		{URL: v.URL, Languages: v.Languages, ConjugationURL: "https://www.verbformen.com/conjugation/?w="},
+		{URL: "http://localhost/", Languages: v.Languages},
+	}
+	for _, other := range tbl {
+		if other.Fingerprint() == v.Fingerprint() {
+			t.Errorf("%+v has the same fingerprint as %+v", other, v)
+		}
+	}
+	// the client and retries don't change cards
+	if (VerbFormen{URL: v.URL, Languages: v.Languages, Retries: 3}).Fingerprint() != v.Fingerprint() {
+		t.Error("fingerprint depends on retries")
+	}
+}
+
+func TestDiskCache_Clear(t *testing.T) {
+	dir := t.TempDir()
+	dc := DiskCache{Dir: dir, TTL: time.Hour}
+	if err := dc.put("verbformen", "", entry{Query: "gehen", At: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dc.put("other", "x", entry{Query: "Haus", At: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	// files which are not entries of providers
+	keep := []string{
+		filepath.Join(dir, "notes.json"),
+		filepath.Join(dir, "verbformen", "README"),
+		filepath.Join(dir, "deep", "nested", "data.json"),
+	}
+	for _, p := range keep {
+		if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := dc.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	st, err := dc.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Entries != 0 {
+		t.Errorf("%d entries left", st.Entries)
+	}
+	for _, p := range keep {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("%s is removed: %v", p, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "other")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("empty provider dir is kept: %v", err)
+	}
+}
+
+func TestDiskCache_Prune(t *testing.T) {
+	dir := t.TempDir()
+	dc := DiskCache{Dir: dir, TTL: time.Hour, MaxEntries: 2}
+	past := time.Now().Add(-time.Minute)
+
+	put := func(q string) {
+		t.Helper()
+		if err := dc.put("verbformen", "", entry{Query: q, At: time.Now(), Card: &Card{Origin: []string{q}}}); err != nil {
+			t.Fatal(err)
+		}
+		// entries are pruned by modification time, make it distinct
+		past = past.Add(time.Second)
+		if err := os.Chtimes(dc.path("verbformen", "", q), past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries := func() int {
+		t.Helper()
+		st, err := dc.Stats()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return st.Entries
+	}
+
+	// the first put prunes, the next ones wait for pruneInterval
+	for _, q := range []string{"eins", "zwei", "drei", "vier"} {
+		put(q)
+	}
+	if n := entries(); n != 4 {
+		t.Fatalf("got %d entries before pruning interval, want 4", n)
+	}
+
+	stamp := filepath.Join(dir, pruneStamp)
+	old := time.Now().Add(-2 * pruneInterval)
+	if err := os.Chtimes(stamp, old, old); err != nil {
+		t.Fatal(err)
+	}
+	put("fünf")
+	if n := entries(); n != 2 {
+		t.Fatalf("got %d entries after pruning, want 2", n)
+	}
+	for _, q := range []string{"vier", "fünf"} {
+		if _, ok := dc.get("verbformen", "", q, time.Now()); !ok {
+			t.Errorf("the newest entry %q is pruned", q)
+		}
+	}
+}
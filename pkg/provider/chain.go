@@ -0,0 +1,254 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+// Card fields could be supplied by different providers
+const (
+	FieldSenses = "senses"
+	FieldForms  = "forms"
+	// FieldGrammar is part of speech with typed forms of nouns and adjectives
+	FieldGrammar     = "grammar"
+	FieldConjugation = "conjugation"
+	FieldExamples    = "examples"
+)
+
+// FieldTranslations is the field of translations in the language, they could be supplied by different providers too
+func FieldTranslations(lang string) string {
+	return "translations " + lang
+}
+
+// ErrNoProviders is returned by Chain without providers
+var ErrNoProviders = errors.New("no providers")
+
+// Provider is a dictionary to look up words in
+type Provider interface {
+	// Name is short name of the provider, e.g. "verbformen"
+	Name() string
+	// Lookup returns Card of the word, the request should be canceled with ctx
+	Lookup(ctx context.Context, s string) (*Card, error)
+}
+
+// MultiProvider is a Provider able to find several cards matching the query, e.g. noun and verb
+type MultiProvider interface {
+	Provider
+	// LookupAll returns all cards matching the query, the best match first
+	LookupAll(ctx context.Context, s string) ([]*Card, error)
+}
+
+// Chain looks up words in providers one by one, until one of them knows the word.
+// With Merge it goes on to fill fields missed in the Card (e.g. forms) from the next providers.
+type Chain struct {
+	Providers []Provider
+	Merge     bool
+	// Languages are preferred languages of translations
+	Languages []string
+}
+
+// GetMeta fills the store.Word with the Card found in providers
+func (c Chain) GetMeta(ctx context.Context, w *store.Word) error {
+	card, err := c.Lookup(ctx, w.Origin)
+	if err != nil {
+		return err
+	}
+
+	card.Apply(w)
+	return nil
+}
+
+// GetCandidates returns words matching the query, the best match first
+func (c Chain) GetCandidates(ctx context.Context, query string) ([]*store.Word, error) {
+	cards, err := c.LookupAll(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	ws := make([]*store.Word, len(cards))
+	for i, card := range cards {
+		ws[i] = store.NewWord(query)
+		card.Apply(ws[i])
+	}
+	return ws, nil
+}
+
+// Lookup returns the best Card found in providers
+func (c Chain) Lookup(ctx context.Context, s string) (*Card, error) {
+	cards, err := c.LookupAll(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	return cards[0], nil
+}
+
+// LookupAll returns cards of the first provider knowing the word, errors of all providers if none of them does.
+// With Merge, fields missed in a card are filled by the next providers cards of the same word.
+func (c Chain) LookupAll(ctx context.Context, s string) ([]*Card, error) {
+	if len(c.Providers) == 0 {
+		return nil, ErrNoProviders
+	}
+
+	var cards []*Card
+	var errs lookupErrors
+	for _, p := range c.Providers {
+		found, err := lookupAll(ctx, p, s)
+		if err != nil {
+			// canceled lookup should not fall back to the next provider
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+
+		if cards == nil {
+			for _, f := range found {
+				card := &Card{Origin: f.Origin, Sources: map[string]string{}}
+				card.merge(f, p.Name())
+				cards = append(cards, card)
+			}
+		} else {
+			for _, card := range cards {
+				if f := sameWord(found, card); f != nil {
+					card.merge(f, p.Name())
+				}
+			}
+		}
+
+		if !c.Merge || allComplete(cards) {
+			break
+		}
+	}
+
+	if cards == nil {
+		return nil, errs
+	}
+	// senses of cached cards could be chosen for other preferred languages
+	for _, card := range cards {
+		if card.Lang != "" {
+			card.Prefer(c.Languages...)
+		}
+	}
+	return cards, nil
+}
+
+// lookupAll returns all cards of the provider, or the only one if it can't find several
+func lookupAll(ctx context.Context, p Provider, s string) ([]*Card, error) {
+	if mp, ok := p.(MultiProvider); ok {
+		return mp.LookupAll(ctx, s)
+	}
+	card, err := p.Lookup(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	return []*Card{card}, nil
+}
+
+// sameWord returns the card of the same word as the given one, nouns are the same with and without the article.
+// Case matters, "Essen" and "essen" are different words.
+func sameWord(cards []*Card, c *Card) *Card {
+	for _, other := range cards {
+		if other.lemma() == c.lemma() {
+			return other
+		}
+	}
+	return nil
+}
+
+// lemma is the word without the article, e.g. "Haus" of "das Haus"
+func (c Card) lemma() string {
+	if len(c.Origin) > 1 && genders[strings.ToLower(c.Origin[0])] != "" {
+		return strings.Join(c.Origin[1:], " ")
+	}
+	return c.Word()
+}
+
+// lookupErrors are errors of all providers, errors.Is and errors.As check each of them
+type lookupErrors []error
+
+func (e lookupErrors) Error() string {
+	ss := make([]string, len(e))
+	for i, err := range e {
+		ss[i] = err.Error()
+	}
+	return strings.Join(ss, "; ")
+}
+
+func (e lookupErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e lookupErrors) As(target any) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func allComplete(cards []*Card) bool {
+	for _, c := range cards {
+		if !c.isComplete() {
+			return false
+		}
+	}
+	return true
+}
+
+// merge fills empty fields of the Card with ones of the other Card, remembering their source
+func (c *Card) merge(other *Card, source string) {
+	if len(c.Senses) == 0 && len(other.Senses) > 0 {
+		c.Senses, c.Lang = other.Senses, other.Lang
+		c.Sources[FieldSenses] = source
+	}
+	for l, ss := range other.Translations {
+		if _, ok := c.Translations[l]; ok || len(ss) == 0 {
+			continue
+		}
+		if c.Translations == nil {
+			c.Translations = map[string][]store.Sense{}
+		}
+		c.Translations[l] = ss
+		c.Sources[FieldTranslations(l)] = source
+	}
+	if len(c.Forms) == 0 && len(other.Forms) > 0 {
+		c.Forms = other.Forms
+		c.Sources[FieldForms] = source
+	}
+	// typed forms are of the part of speech, so they are taken together
+	if c.Pos == "" && other.Pos != "" || c.Pos == other.Pos && !c.hasTypedForms() && other.hasTypedForms() {
+		c.Pos, c.Noun, c.Adjective = other.Pos, other.Noun, other.Adjective
+		c.Sources[FieldGrammar] = source
+	}
+	// a noun is shown with the article
+	if c.Pos == PosNoun && other.Pos == PosNoun && len(other.Origin) > len(c.Origin) {
+		c.Origin = other.Origin
+	}
+	if len(c.Conjugation) == 0 && len(other.Conjugation) > 0 {
+		c.Conjugation = other.Conjugation
+		c.Sources[FieldConjugation] = source
+	}
+	if len(c.Examples) == 0 && len(other.Examples) > 0 {
+		c.Examples = other.Examples
+		c.Sources[FieldExamples] = source
+	}
+}
+
+func (c Card) hasTypedForms() bool {
+	return c.Noun != nil || c.Adjective != nil
+}
+
+func (c Card) isComplete() bool {
+	return len(c.Senses) > 0 && len(c.Forms) > 0
+}
@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+// stub is a Provider with the fixed cards
+type stub struct {
+	name  string
+	cards []*Card
+	err   error
+}
+
+func (s stub) Name() string { return s.name }
+
+func (s stub) Lookup(ctx context.Context, q string) (*Card, error) {
+	cards, err := s.LookupAll(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return cards[0], nil
+}
+
+func (s stub) LookupAll(context.Context, string) ([]*Card, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.cards, nil
+}
+
+func TestChain_LookupAllMerge(t *testing.T) {
+	remote := stub{name: "remote", cards: []*Card{{
+		Origin:       []string{"das", "Haus"},
+		Senses:       store.ParseSenses("house"),
+		Lang:         "en",
+		Translations: map[string][]store.Sense{"en": store.ParseSenses("house")},
+	}}}
+	local := stub{name: "local", cards: []*Card{{
+		Origin:       []string{"das", "Haus"},
+		Senses:       store.ParseSenses("building"),
+		Lang:         "en",
+		Translations: map[string][]store.Sense{"en": store.ParseSenses("building"), "ru": store.ParseSenses("дом")},
+		Forms:        []*Syllable{{Val: "Hauses · Häuser"}},
+		Pos:          PosNoun,
+		Noun:         &NounForms{Article: "das", Gender: "neuter"},
+		Examples:     []store.Example{{Text: "Das Haus ist alt."}},
+	}}}
+
+	cards, err := Chain{Providers: []Provider{remote, local}, Merge: true}.LookupAll(context.Background(), "Haus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 {
+		t.Fatalf("got %d cards", len(cards))
+	}
+	c := cards[0]
+
+	if store.FormatSenses(c.Senses) != "house" || store.FormatSenses(c.Translations["ru"]) != "дом" {
+		t.Errorf("got senses %v, translations %v", c.Senses, c.Translations)
+	}
+	if c.Pos != PosNoun || c.Noun == nil || c.Noun.Gender != "neuter" || len(c.Examples) != 1 {
+		t.Errorf("fields are not merged: %+v", c)
+	}
+	want := map[string]string{
+		FieldSenses:             "remote",
+		FieldForms:              "local",
+		FieldTranslations("en"): "remote",
+		FieldTranslations("ru"): "local",
+		FieldGrammar:            "local",
+		FieldExamples:           "local",
+	}
+	if !reflect.DeepEqual(c.Sources, want) {
+		t.Errorf("got sources %v, want %v", c.Sources, want)
+	}
+}
+
+func TestChain_LookupAllSameWordOnly(t *testing.T) {
+	// the only cards of both providers, but of different words
+	remote := stub{name: "remote", cards: []*Card{{Origin: []string{"essen"}, Senses: store.ParseSenses("eat")}}}
+	local := stub{name: "local", cards: []*Card{{
+		Origin: []string{"Essen"},
+		Senses: store.ParseSenses("food"),
+		Forms:  []*Syllable{{Val: "Essens · Essen"}},
+		Pos:    PosNoun,
+	}}}
+
+	cards, err := Chain{Providers: []Provider{remote, local}, Merge: true}.LookupAll(context.Background(), "essen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 || cards[0].Forms != nil || cards[0].Pos != "" {
+		t.Errorf("cards of different words are merged: %+v", cards[0])
+	}
+	if !reflect.DeepEqual(cards[0].Sources, map[string]string{FieldSenses: "remote"}) {
+		t.Errorf("got sources %v", cards[0].Sources)
+	}
+}
+
+func TestChain_LookupAllNounWithoutArticle(t *testing.T) {
+	dir := t.TempDir()
+	dump := filepath.Join(dir, "dict.tsv")
+	if err := os.WriteFile(dump, []byte("Haus\thouse\tnoun\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	offline := &Offline{Dir: dir}
+	if _, err := offline.Import(dump, ""); err != nil {
+		t.Fatal(err)
+	}
+	srv := pageServer(t)
+	remote := VerbFormen{URL: srv.URL + "/search/", Client: srv.Client()}
+
+	// "Haus" of the dump and "das Haus" of verbformen are the same word
+	cards, err := Chain{Providers: []Provider{offline, remote}, Merge: true}.LookupAll(context.Background(), "Haus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 {
+		t.Fatalf("got %d cards", len(cards))
+	}
+	c := cards[0]
+	if c.Word() != "das Haus" || store.FormatSenses(c.Senses) != "house" {
+		t.Errorf("got %q: %v", c.Word(), c.Senses)
+	}
+	if c.Noun == nil || c.Noun.Plural != "die Häuser" || len(c.Forms) == 0 {
+		t.Errorf("noun forms are not merged: %+v", c)
+	}
+	want := map[string]string{
+		FieldSenses:             offline.Name(),
+		FieldTranslations("en"): offline.Name(),
+		FieldTranslations("ru"): remote.Name(),
+		FieldForms:              remote.Name(),
+		FieldGrammar:            remote.Name(),
+	}
+	if !reflect.DeepEqual(c.Sources, want) {
+		t.Errorf("got sources %v, want %v", c.Sources, want)
+	}
+}
+
+func TestChain_LookupAllFallback(t *testing.T) {
+	failed := stub{name: "failed", err: errors.New("boom")}
+	unknown := stub{name: "unknown", err: ErrNotFound}
+	known := stub{name: "known", cards: []*Card{{Origin: []string{"gehen"}, Senses: store.ParseSenses("go")}}}
+	other := stub{name: "other", cards: []*Card{{Origin: []string{"gehen"}, Forms: []*Syllable{{Val: "geht"}}}}}
+
+	cards, err := Chain{Providers: []Provider{failed, unknown, known, other}}.LookupAll(context.Background(), "gehen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cards[0].Forms != nil || cards[0].Sources[FieldSenses] != "known" {
+		t.Errorf("got %+v, want only the first found card without merge", cards[0])
+	}
+
+	_, err = Chain{Providers: []Provider{failed, unknown}}.LookupAll(context.Background(), "gehen")
+	if err == nil || err.Error() != "failed: boom; unknown: can't find the word" {
+		t.Errorf("got %v", err)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want it to wrap %v", err, ErrNotFound)
+	}
+	if _, err := (Chain{Providers: []Provider{failed}}).LookupAll(context.Background(), "gehen"); errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want other error than %v", err, ErrNotFound)
+	}
+
+	if _, err := (Chain{}).LookupAll(context.Background(), "gehen"); !errors.Is(err, ErrNoProviders) {
+		t.Errorf("got %v, want %v", err, ErrNoProviders)
+	}
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/egregors/karten/pkg/store"
+	"golang.org/x/net/html"
+)
+
+// tenses are names of conjugation tables karten keeps, in order, by names of the tables on the page
+var tenses = []struct {
+	name    string
+	aliases []string
+}{
+	{"Präsens", []string{"präsens", "present"}},
+	{"Präteritum", []string{"präteritum", "preterite", "imperfect", "past"}},
+	{"Konjunktiv I", []string{"konjunktiv i", "subjunctive i", "present subj."}},
+	{"Konjunktiv II", []string{"konjunktiv ii", "subjunctive ii", "imperfect subj."}},
+}
+
+// conjugate requests conjugation tables of the verb from ConjugationURL
+func (v VerbFormen) conjugate(ctx context.Context, verb string) (store.Conjugation, error) {
+	node, err := v.fetch(ctx, v.ConjugationURL+strings.Join(strings.Fields(verb), "+"))
+	if err != nil {
+		return nil, fmt.Errorf("can't get conjugation of %s: %w", verb, err)
+	}
+	return parseConjugation(node), nil
+}
+
+// parseConjugation parses `vTbl` blocks of the page: a heading with the tense name and a table
+// with a row per person, like "ich | geh<i>e</i>". The first table of every tense is taken.
+func parseConjugation(node *html.Node) store.Conjugation {
+	found := map[string]store.Tense{}
+	for _, tbl := range findNodes(node, withClass("vTbl")) {
+		name := tenseName(text(findNode(tbl, func(n *html.Node) bool {
+			return n.Type == html.ElementNode && (n.Data == "h2" || n.Data == "h3" || n.Data == "h4")
+		})))
+		if _, ok := found[name]; name == "" || ok {
+			continue
+		}
+
+		t := store.Tense{Name: name}
+		for _, tr := range findNodes(tbl, func(n *html.Node) bool { return n.Type == html.ElementNode && n.Data == "tr" }) {
+			tds := findNodes(tr, func(n *html.Node) bool { return n.Type == html.ElementNode && n.Data == "td" })
+			// header rows have no forms
+			if len(tds) == 0 {
+				continue
+			}
+			var cells []string
+			for _, td := range findNodes(tr, func(n *html.Node) bool {
+				return n.Type == html.ElementNode && (n.Data == "td" || n.Data == "th")
+			}) {
+				if c := text(td); c != "" {
+					cells = append(cells, c)
+				}
+			}
+			// the pronoun is in its own cell or before the form
+			switch {
+			case len(cells) > 1:
+				t.Forms = append(t.Forms, strings.Join(cells[1:], " "))
+			case len(cells) == 1:
+				_, form, ok := strings.Cut(cells[0], " ")
+				if !ok {
+					form = cells[0]
+				}
+				t.Forms = append(t.Forms, form)
+			}
+		}
+		if len(t.Forms) == len(store.Persons) {
+			found[name] = t
+		}
+	}
+
+	var c store.Conjugation
+	for _, t := range tenses {
+		if f, ok := found[t.name]; ok {
+			c = append(c, f)
+		}
+	}
+	return c
+}
+
+// tenseName returns the name karten keeps of the table heading, empty for other tables
+func tenseName(heading string) string {
+	h := strings.ToLower(heading)
+	for _, t := range tenses {
+		for _, a := range t.aliases {
+			if h == a {
+				return t.name
+			}
+		}
+	}
+	return ""
+}
+
+// text returns text of the node without extra spaces
+func text(n *html.Node) string {
+	var b strings.Builder
+	for _, t := range textNodes(n) {
+		b.WriteString(t.Data)
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,270 @@
+package provider
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+// dictionary dump formats
+const (
+	// FormatTSV is dict.cc or FreeDict-style tab separated dump: word, translations and optional word class.
+	// Gender is taken from dict.cc annotations: "Haus {n}".
+	FormatTSV = "tsv"
+	// FormatJSONL is Wiktionary extract (e.g. by kaikki.org), an entry per line
+	FormatJSONL = "jsonl"
+)
+
+var (
+	annotationRe = regexp.MustCompile(`\{[^}]*\}|\[[^\]]*\]|<[^>]*>`)
+	genderRe     = regexp.MustCompile(`\{(m|f|n)\}`)
+)
+
+var articles = map[string]string{"m": "der", "f": "die", "n": "das", "masculine": "der", "feminine": "die", "neuter": "das"}
+
+// parseDump reads all cards from the dump, by the word. Case matters: "Essen" and "essen" are different cards.
+func parseDump(r io.Reader, format string) (map[string]*Card, error) {
+	cards := map[string]*Card{}
+	// senseOf is index of the sense by word and word class, translations of a class are one sense
+	senseOf := map[string]int{}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var e dumpEntry
+		var err error
+		switch format {
+		case FormatJSONL:
+			e, err = parseJSONLEntry(line)
+		default:
+			e, err = parseTSVEntry(line)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		if e.word == "" || len(e.senses) == 0 {
+			continue
+		}
+
+		key := strings.Join(strings.Fields(e.word), " ")
+		c, ok := cards[key]
+		if !ok {
+			c = &Card{Origin: strings.Fields(e.word)}
+			cards[key] = c
+		}
+		if len(c.Forms) == 0 && e.forms != "" {
+			c.Forms = []*Syllable{{Val: e.forms, Color: Default}}
+		}
+		if c.Pos == "" && e.pos != "" {
+			c.Pos, c.Noun, c.Adjective = e.pos, e.noun, e.adjective
+		}
+
+		for _, s := range e.senses {
+			if e.class == "" {
+				c.Senses = append(c.Senses, s)
+				continue
+			}
+			i, ok := senseOf[key+"\t"+e.class]
+			if !ok {
+				senseOf[key+"\t"+e.class] = len(c.Senses)
+				c.Senses = append(c.Senses, s)
+				continue
+			}
+			c.Senses[i].Translations = appendNew(c.Senses[i].Translations, s.Translations...)
+		}
+	}
+
+	// dumps karten reads are German-English
+	for _, c := range cards {
+		c.Lang = DefaultLanguage
+		c.Translations = map[string][]store.Sense{DefaultLanguage: c.Senses}
+	}
+	return cards, sc.Err()
+}
+
+// dumpEntry is translations of the word from the dump, ones of the same class are merged into one sense
+type dumpEntry struct {
+	word, class, forms string
+	senses             []store.Sense
+
+	// pos is part of speech with typed forms, empty if the dump has no word class
+	pos       string
+	noun      *NounForms
+	adjective *AdjectiveForms
+}
+
+// parseTSVEntry parses line like "Haus {n} [Gebäude]	house	noun"
+func parseTSVEntry(line string) (dumpEntry, error) {
+	cols := strings.Split(line, "\t")
+	if len(cols) < 2 {
+		return dumpEntry{}, fmt.Errorf("want at least 2 columns, got %d", len(cols))
+	}
+
+	e := dumpEntry{
+		word:   strings.Join(strings.Fields(annotationRe.ReplaceAllString(cols[0], "")), " "),
+		senses: store.ParseSenses(annotationRe.ReplaceAllString(cols[1], "")),
+	}
+	if len(cols) > 2 {
+		e.class = strings.TrimSpace(cols[2])
+		e.pos = partOfSpeech(e.class, nil, nil)
+	}
+	if m := genderRe.FindStringSubmatch(cols[0]); m != nil {
+		e.forms = articles[m[1]] + " " + e.word
+		e.pos, e.noun = PosNoun, parseNoun("", []string{articles[m[1]], e.word}, nil)
+	}
+	return e, nil
+}
+
+// wiktionaryEntry is the part of Wiktionary extract entry karten uses
+type wiktionaryEntry struct {
+	Word   string   `json:"word"`
+	Pos    string   `json:"pos"`
+	Tags   []string `json:"tags"`
+	Senses []struct {
+		Glosses []string `json:"glosses"`
+		Tags    []string `json:"tags"`
+		FormOf  []any    `json:"form_of"`
+	} `json:"senses"`
+	Forms []struct {
+		Form string   `json:"form"`
+		Tags []string `json:"tags"`
+	} `json:"forms"`
+	HeadTemplates []struct {
+		Expansion string `json:"expansion"`
+	} `json:"head_templates"`
+}
+
+// parseJSONLEntry parses Wiktionary extract entry, inflected forms of other words are skipped
+func parseJSONLEntry(line string) (dumpEntry, error) {
+	var we wiktionaryEntry
+	if err := json.Unmarshal([]byte(line), &we); err != nil {
+		return dumpEntry{}, err
+	}
+
+	// Wiktionary senses are different meanings, so they are not grouped by word class
+	e := dumpEntry{word: we.Word}
+	for _, s := range we.Senses {
+		if len(s.FormOf) > 0 || len(s.Glosses) == 0 {
+			continue
+		}
+		// the last gloss is the most specific one
+		e.senses = append(e.senses, store.ParseSenses(s.Glosses[len(s.Glosses)-1])...)
+	}
+
+	form := func(tags ...string) string {
+		for _, f := range we.Forms {
+			if hasAll(f.Tags, tags) {
+				return f.Form
+			}
+		}
+		return ""
+	}
+
+	e.pos = partOfSpeech(we.Pos, nil, nil)
+	switch we.Pos {
+	case "noun":
+		e.forms = join(" ", we.gender(), we.Word)
+		pl := form("nominative", "plural")
+		if pl == "" {
+			pl = form("plural")
+		}
+		if pl != "" {
+			e.forms += " · die " + pl
+		}
+		// "-" is for no plural
+		plural := "-"
+		if pl != "" {
+			plural = pl
+		}
+		e.noun = parseNoun("", []string{we.gender(), we.Word}, []string{form("genitive", "singular"), plural})
+	case "verb":
+		aux := map[string]string{"haben": "hat", "sein": "ist"}[form("auxiliary")]
+		e.forms = join(" · ",
+			form("present", "singular", "third-person"),
+			form("preterite", "singular", "third-person"),
+			join(" ", aux, form("participle", "past")),
+		)
+	case "adj":
+		e.adjective = &AdjectiveForms{}
+		if cmp := form("comparative"); cmp != "" {
+			e.forms = join(" · ", we.Word, cmp, form("superlative"))
+			e.adjective = parseAdjective([]string{we.Word, cmp, form("superlative")})
+		}
+	}
+	return e, nil
+}
+
+// gender returns article of the noun by tags, or by head template like "Haus n (strong, ...)"
+func (we wiktionaryEntry) gender() string {
+	tags := we.Tags
+	for _, s := range we.Senses {
+		tags = append(tags, s.Tags...)
+	}
+	for _, t := range tags {
+		if a, ok := articles[t]; ok && len(t) > 1 {
+			return a
+		}
+	}
+	for _, h := range we.HeadTemplates {
+		fs := strings.Fields(strings.TrimPrefix(h.Expansion, we.Word))
+		if len(fs) > 0 {
+			if a, ok := articles[fs[0]]; ok {
+				return a
+			}
+		}
+	}
+	return ""
+}
+
+// join joins non-empty forms by the separator
+func join(sep string, fs ...string) string {
+	var parts []string
+	for _, f := range fs {
+		if f != "" {
+			parts = append(parts, f)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+func hasAll(tags, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, t := range tags {
+			if t == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func appendNew(ss []string, more ...string) []string {
+	for _, m := range more {
+		found := false
+		for _, s := range ss {
+			if s == m {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ss = append(ss, m)
+		}
+	}
+	return ss
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"strings"
+
+	"github.com/egregors/karten/pkg/store"
+	"golang.org/x/net/html"
+)
+
+// parseExamples parses `vBsp` blocks of the section: the German sentence with the word in bold,
+// and its translations in elements with lang attribute, like
+//
+//	<div class="vBsp"><p>Ich <b>ging</b> nach Hause.</p><p lang="en">I went home.</p></div>
+func parseExamples(node *html.Node) []store.Example {
+	var es []store.Example
+	for _, bsp := range findNodes(node, withClass("vBsp")) {
+		e := store.Example{}
+		var de []string
+		for _, n := range textNodes(bsp) {
+			lang := ""
+			for p := n.Parent; p != bsp && p != nil; p = p.Parent {
+				if lang = langOf(p); lang != "" {
+					break
+				}
+			}
+			if lang != "" && lang != "de" {
+				if e.Translations == nil {
+					e.Translations = map[string]string{}
+				}
+				e.Translations[lang] += n.Data
+				continue
+			}
+			de = append(de, n.Data)
+			if e.Word == "" && n.Parent.Data == "b" {
+				e.Word = strings.TrimSpace(n.Data)
+			}
+		}
+
+		for l, t := range e.Translations {
+			e.Translations[l] = strings.Join(strings.Fields(t), " ")
+		}
+		if e.Text = strings.Join(strings.Fields(strings.Join(de, "")), " "); e.Text != "" {
+			es = append(es, e)
+		}
+	}
+	return es
+}
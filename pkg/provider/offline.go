@@ -0,0 +1,322 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+const (
+	// dumpName is name of imported dump in the Offline dir, with extension of the format
+	dumpName = "dump"
+	// indexName is name of the index in the Offline dir: lines "normalized word<TAB>card JSON" sorted by
+	// the normalized word, words differing in case only have own lines.
+	// The version is bumped when cards get new fields, so older indexes are built again.
+	indexName = "index.v3.tsv"
+)
+
+// oldIndexNames are names of older index versions, removed once the current one is built
+var oldIndexNames = []string{"index.tsv", "index.v2.tsv"}
+
+// ErrNoDictionary is returned by Offline provider without imported dictionary
+var ErrNoDictionary = errors.New("no offline dictionary, import one by `karten dict import`")
+
+// Offline is a provider answering from a local dictionary dump. The dump is indexed on first use,
+// so exact and prefix lookups are binary searches in the sorted index file.
+type Offline struct {
+	Dir string
+
+	mu sync.Mutex
+}
+
+// Name returns name of the provider
+func (o *Offline) Name() string {
+	return "offline"
+}
+
+// GetMeta fills the store.Word with the Card from the dictionary
+func (o *Offline) GetMeta(ctx context.Context, w *store.Word) error {
+	card, err := o.Lookup(ctx, w.Origin)
+	if err != nil {
+		return err
+	}
+
+	card.Apply(w)
+	return nil
+}
+
+// Lookup returns Card of the word from the dictionary. The word of the same case is preferred,
+// e.g. "essen" is the verb and "Essen" is the noun, otherwise any case fits.
+func (o *Offline) Lookup(_ context.Context, s string) (*Card, error) {
+	key, word := normalize(s), strings.Join(strings.Fields(s), " ")
+	var card *Card
+	err := o.scan(key, func(k string, c *Card) bool {
+		if k != key {
+			return false
+		}
+		if card == nil || c.Word() == word {
+			card = c
+		}
+		return c.Word() != word
+	})
+	if err != nil {
+		return nil, err
+	}
+	if card == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, s)
+	}
+	return card, nil
+}
+
+// Search returns up to limit words starting with the prefix, in alphabetical order
+func (o *Offline) Search(prefix string, limit int) ([]string, error) {
+	var words []string
+	err := o.scan(normalize(prefix), func(_ string, c *Card) bool {
+		words = append(words, c.Word())
+		return len(words) < limit
+	})
+	return words, err
+}
+
+// Import copies the dump into the dictionary dir and indexes it, returns number of indexed words.
+// Format is FormatTSV or FormatJSONL, guessed by the file extension if empty.
+func (o *Offline) Import(path, format string) (int, error) {
+	if format == "" {
+		format = FormatTSV
+		if ext := strings.ToLower(filepath.Ext(path)); ext == ".jsonl" || ext == ".json" {
+			format = FormatJSONL
+		}
+	}
+	if format != FormatTSV && format != FormatJSONL {
+		return 0, fmt.Errorf("unknown dictionary format %q", format)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err := os.MkdirAll(o.Dir, os.ModePerm); err != nil {
+		return 0, err
+	}
+	src, err := filepath.Abs(path)
+	if err != nil {
+		return 0, err
+	}
+	dst, err := filepath.Abs(o.dumpPath(format))
+	if err != nil {
+		return 0, err
+	}
+	// the imported dump is just indexed again
+	if src != dst {
+		if err := copyFile(src, dst); err != nil {
+			return 0, fmt.Errorf("can't copy dictionary: %w", err)
+		}
+	}
+	// the other format dump is replaced by the new one
+	for _, f := range []string{FormatTSV, FormatJSONL} {
+		if f != format {
+			_ = os.Remove(o.dumpPath(f))
+		}
+	}
+
+	return o.buildIndex(format)
+}
+
+// scan calls fn for index entries starting with the prefix, until it returns false
+func (o *Offline) scan(prefix string, fn func(key string, c *Card) bool) error {
+	if err := o.ensureIndex(); err != nil {
+		return err
+	}
+
+	f, err := os.Open(filepath.Join(o.Dir, indexName))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	start, err := findFirst(f, info.Size(), prefix)
+	if err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(io.NewSectionReader(f, start, info.Size()-start))
+	for {
+		line, err := r.ReadString('\n')
+		if line == "" && err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		key, data, _ := strings.Cut(strings.TrimSuffix(line, "\n"), "\t")
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		var c Card
+		if err := json.Unmarshal([]byte(data), &c); err != nil {
+			return fmt.Errorf("broken dictionary index, import the dictionary again: %w", err)
+		}
+		if !fn(key, &c) {
+			return nil
+		}
+	}
+}
+
+// ensureIndex builds the index if there is no one, or the dump is newer
+func (o *Offline) ensureIndex() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, format := range []string{FormatTSV, FormatJSONL} {
+		dump, err := os.Stat(o.dumpPath(format))
+		if err != nil {
+			continue
+		}
+		index, err := os.Stat(filepath.Join(o.Dir, indexName))
+		if err == nil && !index.ModTime().Before(dump.ModTime()) {
+			return nil
+		}
+		_, err = o.buildIndex(format)
+		return err
+	}
+	return ErrNoDictionary
+}
+
+// buildIndex parses the dump and writes the sorted index
+func (o *Offline) buildIndex(format string) (int, error) {
+	f, err := os.Open(o.dumpPath(format))
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	cards, err := parseDump(f, format)
+	if err != nil {
+		return 0, fmt.Errorf("can't parse dictionary: %w", err)
+	}
+
+	words := make([]string, 0, len(cards))
+	for w := range cards {
+		words = append(words, w)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if ki, kj := normalize(words[i]), normalize(words[j]); ki != kj {
+			return ki < kj
+		}
+		return words[i] < words[j]
+	})
+
+	tmp, err := os.CreateTemp(o.Dir, ".index-*")
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	w := bufio.NewWriter(tmp)
+	for _, word := range words {
+		data, err := json.Marshal(cards[word])
+		if err != nil {
+			_ = tmp.Close()
+			return 0, err
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\n", normalize(word), data); err != nil {
+			_ = tmp.Close()
+			return 0, err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(o.Dir, indexName)); err != nil {
+		return 0, err
+	}
+	for _, name := range oldIndexNames {
+		_ = os.Remove(filepath.Join(o.Dir, name))
+	}
+	return len(words), nil
+}
+
+func (o *Offline) dumpPath(format string) string {
+	return filepath.Join(o.Dir, dumpName+"."+format)
+}
+
+// findFirst returns offset of the first line with key >= k in the sorted file
+func findFirst(r io.ReaderAt, size int64, k string) (int64, error) {
+	lo, hi := int64(0), size
+	for lo < hi {
+		mid := (lo + hi) / 2
+		start, err := lineStart(r, size, mid)
+		if err != nil {
+			return 0, err
+		}
+		if start >= hi {
+			hi = mid
+			continue
+		}
+
+		line, err := bufio.NewReader(io.NewSectionReader(r, start, size-start)).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return 0, err
+		}
+		key, _, _ := strings.Cut(strings.TrimSuffix(line, "\n"), "\t")
+		if key < k {
+			lo = start + int64(len(line))
+		} else {
+			hi = mid
+		}
+	}
+	return lineStart(r, size, lo)
+}
+
+// lineStart returns offset of the first line starting at pos or after it
+func lineStart(r io.ReaderAt, size, pos int64) (int64, error) {
+	if pos == 0 || pos >= size {
+		return pos, nil
+	}
+	br := bufio.NewReader(io.NewSectionReader(r, pos-1, size-pos+1))
+	skipped, err := br.ReadString('\n')
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return size, nil
+		}
+		return 0, err
+	}
+	return pos - 1 + int64(len(skipped)), nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(filepath.Clean(src))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(filepath.Clean(dst), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
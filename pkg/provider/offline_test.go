@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+func TestOffline_Lookup(t *testing.T) {
+	type want struct {
+		pos, senses, forms string
+		noun               *NounForms
+		adjective          *AdjectiveForms
+	}
+
+	tbl := []struct {
+		dump  string
+		words map[string]want
+	}{
+		{"dict.tsv", map[string]want{
+			"Haus": {
+				pos: PosNoun, senses: "house, building", forms: "das Haus",
+				noun: &NounForms{Article: "das", Gender: "neuter"},
+			},
+			"gehen":   {pos: PosVerb, senses: "to go, to walk"},
+			"schnell": {pos: PosAdjective, senses: "fast"},
+			"da":      {senses: "there"},
+			// words differing in case only are different cards
+			"essen": {pos: PosVerb, senses: "to eat"},
+			"Essen": {
+				pos: PosNoun, senses: "food, meal", forms: "das Essen",
+				noun: &NounForms{Article: "das", Gender: "neuter"},
+			},
+		}},
+		{"dict.jsonl", map[string]want{
+			"Haus": {
+				pos: PosNoun, senses: "house; building", forms: "das Haus · die Häuser",
+				noun: &NounForms{Article: "das", Gender: "neuter", Genitive: "des Hauses", Plural: "die Häuser"},
+			},
+			"Obst": {
+				pos: PosNoun, senses: "fruit", forms: "das Obst",
+				noun: &NounForms{Article: "das", Gender: "neuter", Genitive: "des Obstes"},
+			},
+			"gehen": {pos: PosVerb, senses: "to walk", forms: "geht · ging · ist gegangen"},
+			"schnell": {
+				pos: PosAdjective, senses: "fast", forms: "schnell · schneller · am schnellsten",
+				adjective: &AdjectiveForms{Comparative: "schneller", Superlative: "am schnellsten"},
+			},
+		}},
+	}
+
+	for _, tt := range tbl {
+		t.Run(tt.dump, func(t *testing.T) {
+			o := &Offline{Dir: t.TempDir()}
+			if _, err := o.Import(filepath.Join("testdata", tt.dump), ""); err != nil {
+				t.Fatal(err)
+			}
+
+			for word, w := range tt.words {
+				c, err := o.Lookup(context.Background(), word)
+				if err != nil {
+					t.Fatalf("%s: %v", word, err)
+				}
+				if got := store.FormatSenses(c.Senses); got != w.senses {
+					t.Errorf("%s: got senses %q, want %q", word, got, w.senses)
+				}
+				if c.Lang != DefaultLanguage || !reflect.DeepEqual(c.Translations, map[string][]store.Sense{DefaultLanguage: c.Senses}) {
+					t.Errorf("%s: got %q senses, translations %v", word, c.Lang, c.Translations)
+				}
+				if got := join("", formsOf(c)...); got != w.forms {
+					t.Errorf("%s: got forms %q, want %q", word, got, w.forms)
+				}
+				if c.Pos != w.pos || !reflect.DeepEqual(c.Noun, w.noun) || !reflect.DeepEqual(c.Adjective, w.adjective) {
+					t.Errorf("%s: got %q %+v %+v, want %q %+v %+v", word, c.Pos, c.Noun, c.Adjective, w.pos, w.noun, w.adjective)
+				}
+			}
+		})
+	}
+}
+
+func TestOffline_LookupAnyCase(t *testing.T) {
+	o := &Offline{Dir: t.TempDir()}
+	if _, err := o.Import(filepath.Join("testdata", "dict.tsv"), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// there is no word of the same case, so any fits
+	for q, want := range map[string]string{"haus": "Haus", " GEHEN ": "gehen"} {
+		c, err := o.Lookup(context.Background(), q)
+		if err != nil {
+			t.Fatalf("%q: %v", q, err)
+		}
+		if c.Word() != want {
+			t.Errorf("%q: got %q, want %q", q, c.Word(), want)
+		}
+	}
+
+	ws, err := o.Search("ess", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ws, []string{"Essen", "essen"}) {
+		t.Errorf("got %q", ws)
+	}
+}
+
+func TestOffline_OldIndexIsRebuilt(t *testing.T) {
+	dir := t.TempDir()
+	o := &Offline{Dir: dir}
+	if _, err := o.Import(filepath.Join("testdata", "dict.tsv"), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// an index of the previous version, without typed fields
+	if err := os.Rename(filepath.Join(dir, indexName), filepath.Join(dir, "index.tsv")); err != nil {
+		t.Fatal(err)
+	}
+	c, err := (&Offline{Dir: dir}).Lookup(context.Background(), "Haus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Pos != PosNoun {
+		t.Errorf("got part of speech %q", c.Pos)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "index.tsv")); !os.IsNotExist(err) {
+		t.Errorf("old index is kept: %v", err)
+	}
+}
+
+// formsOf returns values of the card forms
+func formsOf(c *Card) []string {
+	fs := make([]string, len(c.Forms))
+	for i, f := range c.Forms {
+		fs[i] = f.Val
+	}
+	return fs
+}
@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"strings"
+	"unicode"
+)
+
+// parts of speech of cards
+const (
+	PosVerb      = "verb"
+	PosNoun      = "noun"
+	PosAdjective = "adjective"
+)
+
+// genders of nouns by article
+var genders = map[string]string{"der": "masculine", "die": "feminine", "das": "neuter"}
+
+// NounForms are grammar forms of a noun
+type NounForms struct {
+	Article string `json:"article,omitempty"`
+	Gender  string `json:"gender,omitempty"`
+	// Genitive is genitive singular with the article, e.g. "des Hauses"
+	Genitive string `json:"genitive,omitempty"`
+	// Plural is nominative plural with the article, empty for nouns without plural
+	Plural string `json:"plural,omitempty"`
+	// Declension is the declension pattern, endings of genitive and plural like "-es, -¨er"
+	Declension string `json:"declension,omitempty"`
+}
+
+// AdjectiveForms are degrees of comparison of an adjective, empty for incomparable ones
+type AdjectiveForms struct {
+	Comparative string `json:"comparative,omitempty"`
+	Superlative string `json:"superlative,omitempty"`
+}
+
+// Grammar renders part of speech and typed forms of the Card, e.g. "noun, neuter, plural: die Häuser"
+func (c Card) Grammar() string {
+	parts := []string{c.Pos}
+	switch {
+	case c.Noun != nil:
+		parts = append(parts, c.Noun.Gender)
+		if c.Noun.Genitive != "" {
+			parts = append(parts, "genitive: "+c.Noun.Genitive)
+		}
+		if c.Noun.Plural != "" {
+			parts = append(parts, "plural: "+c.Noun.Plural)
+		}
+		if c.Noun.Declension != "" {
+			parts = append(parts, "declension: "+c.Noun.Declension)
+		}
+	case c.Adjective != nil:
+		if c.Adjective.Comparative != "" {
+			parts = append(parts, "comparative: "+c.Adjective.Comparative)
+		}
+		if c.Adjective.Superlative != "" {
+			parts = append(parts, "superlative: "+c.Adjective.Superlative)
+		}
+	}
+	return join(", ", parts...)
+}
+
+// partOfSpeech recognizes the part of speech by the word info line of the page (like "noun · neuter · -es, -¨er"),
+// or by the origin and the forms if there is no info
+func partOfSpeech(info string, origin, forms []string) string {
+	for _, t := range strings.FieldsFunc(strings.ToLower(info), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		switch t {
+		case "noun", "substantiv":
+			return PosNoun
+		case "adjective", "adj", "adjektiv":
+			return PosAdjective
+		case "verb":
+			return PosVerb
+		}
+	}
+
+	switch {
+	case len(origin) > 1 && genders[strings.ToLower(origin[0])] != "":
+		return PosNoun
+	case len(forms) == 3 && strings.HasPrefix(forms[2], "am "):
+		return PosAdjective
+	case len(forms) > 0:
+		return PosVerb
+	}
+	return ""
+}
+
+// parseNoun makes NounForms of the noun with origin like "das Haus" and forms like "Hauses · Häuser"
+func parseNoun(info string, origin, forms []string) *NounForms {
+	n := &NounForms{}
+	if len(origin) > 1 {
+		n.Article = strings.ToLower(origin[0])
+		n.Gender = genders[n.Article]
+	}
+
+	// forms are shown with or without articles, and "-" is for no plural
+	withArticle := func(form, article string) string {
+		if form == "" || form == "-" || article == "" {
+			return strings.Trim(form, "-")
+		}
+		if fs := strings.Fields(form); len(fs) > 1 && (genders[fs[0]] != "" || fs[0] == "des") {
+			return form
+		}
+		return article + " " + form
+	}
+	genitive := map[string]string{"der": "des", "die": "der", "das": "des"}[n.Article]
+	if len(forms) > 0 {
+		n.Genitive = withArticle(forms[0], genitive)
+	}
+	if len(forms) > 1 {
+		n.Plural = withArticle(forms[1], "die")
+	}
+
+	// the pattern is the part of the info starting with an ending
+	var endings []string
+	for _, p := range strings.Split(info, "·") {
+		if p = strings.TrimSpace(p); strings.HasPrefix(p, "-") {
+			endings = append(endings, p)
+		}
+	}
+	n.Declension = strings.Join(endings, ", ")
+	return n
+}
+
+// parseAdjective makes AdjectiveForms of forms like "schnell · schneller · am schnellsten"
+func parseAdjective(forms []string) *AdjectiveForms {
+	if len(forms) < 3 {
+		return &AdjectiveForms{}
+	}
+	return &AdjectiveForms{Comparative: forms[1], Superlative: forms[2]}
+}
+
+// splitForms splits the forms line by "·" separators
+func splitForms(fs []*Syllable) []string {
+	var b strings.Builder
+	for _, f := range fs {
+		b.WriteString(f.Val)
+	}
+
+	var forms []string
+	for _, f := range strings.Split(b.String(), "·") {
+		if f = strings.Join(strings.Fields(f), " "); f != "" {
+			forms = append(forms, f)
+		}
+	}
+	return forms
+}
@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+// ansiRe matches terminal color sequences
+var ansiRe = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// pages of a verb, nouns and adjectives are hand-written, see testdata/README.md
+func TestVerbFormen_LookupTyped(t *testing.T) {
+	srv := pageServer(t)
+	v := VerbFormen{URL: srv.URL + "/search/", Client: srv.Client()}
+
+	tbl := []struct {
+		word      string
+		pos       string
+		noun      *NounForms
+		adjective *AdjectiveForms
+		str       string
+		grammar   string
+		senses    string
+	}{
+		{
+			word: "gehen", pos: PosVerb,
+			str:     "gehen\ngeht · ging · ist gegangen",
+			grammar: "verb",
+			senses:  "go, walk; work, function",
+		},
+		{
+			word: "haus", pos: PosNoun,
+			noun: &NounForms{
+				Article: "das", Gender: "neuter", Genitive: "des Hauses", Plural: "die Häuser", Declension: "-es, -¨er",
+			},
+			str:     "das Haus\nHauses · Häuser",
+			grammar: "noun, neuter, genitive: des Hauses, plural: die Häuser, declension: -es, -¨er",
+			senses:  "house, building; home",
+		},
+		{
+			word: "obst", pos: PosNoun,
+			noun:    &NounForms{Article: "das", Gender: "neuter", Genitive: "des Obstes", Declension: "-es, -"},
+			str:     "das Obst\nObstes · -",
+			grammar: "noun, neuter, genitive: des Obstes, declension: -es, -",
+			senses:  "fruit",
+		},
+		{
+			word: "schnell", pos: PosAdjective,
+			adjective: &AdjectiveForms{Comparative: "schneller", Superlative: "am schnellsten"},
+			str:       "schnell\nschnell · schneller · am schnellsten",
+			grammar:   "adjective, comparative: schneller, superlative: am schnellsten",
+			senses:    "fast, quick; rapid",
+		},
+		{
+			word: "tot", pos: PosAdjective,
+			adjective: &AdjectiveForms{},
+			str:       "tot\n",
+			grammar:   "adjective",
+			senses:    "dead",
+		},
+	}
+
+	for _, tt := range tbl {
+		t.Run(tt.word, func(t *testing.T) {
+			card, err := v.Lookup(context.Background(), tt.word)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if card.Pos != tt.pos {
+				t.Errorf("got part of speech %q, want %q", card.Pos, tt.pos)
+			}
+			if !reflect.DeepEqual(card.Noun, tt.noun) {
+				t.Errorf("got noun %+v, want %+v", card.Noun, tt.noun)
+			}
+			if !reflect.DeepEqual(card.Adjective, tt.adjective) {
+				t.Errorf("got adjective %+v, want %+v", card.Adjective, tt.adjective)
+			}
+			if got := ansiRe.ReplaceAllString(card.String(), ""); got != tt.str {
+				t.Errorf("got String() %q, want %q", got, tt.str)
+			}
+			if got := card.Grammar(); got != tt.grammar {
+				t.Errorf("got Grammar() %q, want %q", got, tt.grammar)
+			}
+			if got := store.FormatSenses(card.Senses); got != tt.senses {
+				t.Errorf("got senses %q, want %q", got, tt.senses)
+			}
+		})
+	}
+}
+
+func TestVerbFormen_LookupAllTyped(t *testing.T) {
+	srv := pageServer(t)
+	v := VerbFormen{URL: srv.URL + "/search/", Client: srv.Client()}
+
+	cards, err := v.LookupAll(context.Background(), "bank")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"noun, feminine, genitive: der Bank, plural: die Bänke, declension: -, -¨e",
+		"noun, feminine, genitive: der Bank, plural: die Banken, declension: -, -en",
+		"verb",
+	}
+	var got []string
+	for _, c := range cards {
+		got = append(got, c.Grammar())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got cards %q, want %q", got, want)
+	}
+}
+
+func TestPartOfSpeech(t *testing.T) {
+	tbl := []struct {
+		info   string
+		origin []string
+		forms  []string
+		want   string
+	}{
+		{"noun · neuter · -es, -¨er", []string{"das", "Haus"}, nil, PosNoun},
+		{"Substantiv, maskulin", []string{"Baum"}, nil, PosNoun},
+		{"adjective · comparable", []string{"schnell"}, nil, PosAdjective},
+		{"Adj.", nil, nil, PosAdjective},
+		{"verb · irregular · sein", []string{"gehen"}, nil, PosVerb},
+		// by origin and forms without the info
+		{"", []string{"Die", "Bank"}, nil, PosNoun},
+		{"", []string{"schnell"}, []string{"schnell", "schneller", "am schnellsten"}, PosAdjective},
+		{"", []string{"gehen"}, []string{"geht", "ging", "ist gegangen"}, PosVerb},
+		{"", []string{"da"}, nil, ""},
+	}
+
+	for _, tt := range tbl {
+		if got := partOfSpeech(tt.info, tt.origin, tt.forms); got != tt.want {
+			t.Errorf("%q %v %v: got %q, want %q", tt.info, tt.origin, tt.forms, got, tt.want)
+		}
+	}
+}
@@ -1,9 +1,14 @@
 package provider
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/egregors/karten/pkg/store"
 	"github.com/muesli/termenv"
@@ -32,12 +37,77 @@ type Syllable struct {
 
 // Card is representation of word card from VerbFormen
 type Card struct {
-	Origin      []string
-	Translation []string
-	Forms       []*Syllable
+	Origin []string      `json:"origin"`
+	Senses []store.Sense `json:"senses"`
+	Forms  []*Syllable   `json:"forms"`
+	// Translations are senses in all languages found, by language code
+	Translations map[string][]store.Sense `json:"translations,omitempty"`
+	// Lang is language of Senses
+	Lang string `json:"lang,omitempty"`
+	// Sources are names of providers supplied the fields, by field name
+	Sources map[string]string `json:"sources,omitempty"`
+
+	// Pos is part of speech: PosVerb, PosNoun or PosAdjective, empty if unknown
+	Pos string `json:"pos,omitempty"`
+	// Noun are forms of a noun card
+	Noun *NounForms `json:"noun,omitempty"`
+	// Adjective are forms of an adjective card
+	Adjective *AdjectiveForms `json:"adjective,omitempty"`
+	// Conjugation are conjugation tables of a verb card, if the provider fetches them
+	Conjugation store.Conjugation `json:"conjugation,omitempty"`
+	// Examples are example sentences with translations, if the page has them
+	Examples []store.Example `json:"examples,omitempty"`
 }
 
-func (c Card) toStyledString() string {
+// DefaultLanguage is language of translations if there are no preferred ones
+const DefaultLanguage = "en"
+
+// Prefer chooses Senses in the first of languages the Card has translations in,
+// in DefaultLanguage or any other one if it has none of them.
+func (c *Card) Prefer(langs ...string) {
+	all := make([]string, 0, len(c.Translations))
+	for l := range c.Translations {
+		all = append(all, l)
+	}
+	sort.Strings(all)
+
+	order := append(append(append([]string{}, langs...), DefaultLanguage), all...)
+	for _, l := range order {
+		if ss := c.Translations[l]; len(ss) > 0 {
+			c.Senses, c.Lang = ss, l
+			return
+		}
+	}
+}
+
+// Word returns the Card origin as a single string
+func (c Card) Word() string {
+	return strings.Join(c.Origin, " ")
+}
+
+// Apply fills the store.Word with the Card data. Translations in all languages, including the one of Senses,
+// are kept in the store.Word too, to show the Word in other language if it's preferred.
+func (c Card) Apply(w *store.Word) {
+	w.Origin = c.Word()
+	w.Senses = c.Senses
+	w.Meta = c.String()
+	w.Conjugation = c.Conjugation
+	w.Examples = c.Examples
+
+	w.Translations = map[string][]store.Sense{}
+	for l, ss := range c.Translations {
+		w.Translations[l] = ss
+	}
+	if c.Lang != "" && len(c.Senses) > 0 {
+		w.Translations[c.Lang] = c.Senses
+	}
+	if len(w.Translations) == 0 {
+		w.Translations = nil
+	}
+}
+
+// String renders the Card origin and colored forms
+func (c Card) String() string {
 	s := strings.Join(c.Origin, " ") + "\n"
 
 	for _, w := range c.Forms {
@@ -55,158 +125,324 @@ func (c Card) toStyledString() string {
 	return s
 }
 
-// IsEmpty returns false is Card is empty
+// IsEmpty returns false is Card is empty. Nouns and adjectives could have no forms
+// (e.g. without plural or incomparable), verbs always have them.
 func (c Card) IsEmpty() bool {
-	if len(c.Origin) == 0 || len(c.Translation) == 0 || len(c.Forms) == 0 {
+	if len(c.Origin) == 0 || len(c.Senses) == 0 {
 		return true
 	}
-	return false
+	return len(c.Forms) == 0 && c.Pos != PosNoun && c.Pos != PosAdjective
 }
 
+const (
+	// UserAgent is sent with all requests to providers
+	UserAgent = "karten (+https://github.com/egregors/karten)"
+	// DefaultBackoff is delay before the first retry, it's doubled for every next one
+	DefaultBackoff = 500 * time.Millisecond
+)
+
 // VerbFormen – remove service to get translations and forms: https://www.verbformen.com/
 type VerbFormen struct {
+	// URL is search URL, the word is appended to it
 	URL string
+	// Client is used for all requests, http.DefaultClient if nil
+	Client *http.Client
+	// Retries is number of retries of a request failed with 5xx or 429
+	Retries int
+	// Backoff is delay before the first retry, DefaultBackoff if zero
+	Backoff time.Duration
+	// Languages are preferred languages of translations, DefaultLanguage if none of them found
+	Languages []string
+	// ConjugationURL is URL of conjugation tables, the verb is appended to it. Tables are not fetched if empty.
+	ConjugationURL string
+}
+
+// statusError is unexpected HTTP status of the response
+type statusError struct {
+	code       int
+	retryAfter time.Duration
+}
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("unexpected response: %d %s", e.code, http.StatusText(e.code))
+}
+
+// temporary checks if the request could succeed later
+func (e statusError) temporary() bool {
+	return e.code == http.StatusTooManyRequests || e.code >= http.StatusInternalServerError
+}
+
+// Name returns name of the provider
+func (v VerbFormen) Name() string {
+	return "verbformen"
+}
+
+// Fingerprint returns settings changing found cards: URLs and preferred languages
+func (v VerbFormen) Fingerprint() string {
+	return fmt.Sprintf("url=%s;conjugation=%s;languages=%s", v.URL, v.ConjugationURL, strings.Join(v.Languages, ","))
 }
 
 // GetMeta hits remove service to get Metadata for particular store.Word
-func (v VerbFormen) GetMeta(w *store.Word) error {
-	card, err := v.getCard(w.Origin)
+func (v VerbFormen) GetMeta(ctx context.Context, w *store.Word) error {
+	card, err := v.Lookup(ctx, w.Origin)
 	if err != nil {
 		return err
 	}
 
-	w.Origin = strings.Join(card.Origin, " ")
-	w.Translation = strings.Join(card.Translation, " ")
-	w.Meta = card.toStyledString()
-
+	card.Apply(w)
 	return nil
 }
 
-// GetVerbFormenCard requests Word card from verbformen site
-func (v VerbFormen) getCard(s string) (*Card, error) {
-	ws := strings.Split(s, " ")
-	r, err := http.Get(v.URL + strings.Join(ws, "+"))
+// Lookup requests Word card from verbformen site, the request is canceled with ctx
+func (v VerbFormen) Lookup(ctx context.Context, s string) (*Card, error) {
+	cards, err := v.LookupAll(ctx, s)
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = r.Body.Close() }()
-	node, err := html.Parse(r.Body)
+	return cards[0], nil
+}
+
+// LookupAll requests all Word cards matching the query (e.g. noun and verb), the best match first
+func (v VerbFormen) LookupAll(ctx context.Context, s string) ([]*Card, error) {
+	ws := strings.Split(s, " ")
+	node, err := v.fetch(ctx, v.URL+strings.Join(ws, "+"))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("can't look up %s: %w", s, err)
 	}
 
-	// just need first <section> in page
-	section := findNode(node, func(n *html.Node) bool {
+	// every <section> in page is a candidate
+	sections := findNodes(node, func(n *html.Node) bool {
 		return n.Type == html.ElementNode && n.Data == "section"
 	})
 
-	card := &Card{}
-	if section != nil {
+	var cards []*Card
+	seen := map[string]bool{}
+	for _, section := range sections {
+		card := &Card{}
 		parseNode(section, card)
+		card.Prefer(v.Languages...)
+		if card.IsEmpty() {
+			continue
+		}
+		// nested sections are parsed as the same card
+		key := card.String() + store.FormatSenses(card.Senses)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		cards = append(cards, card)
 	}
 
-	if card.IsEmpty() {
-		return nil, fmt.Errorf("can't find the word: %s", s)
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, s)
 	}
 
-	return card, nil
-}
-
-// NewVerbFormenCardFromNode takes HTML node with `section` from verbformen.com and
-// parses it into Card struct
-// todo: too complex, need refactoring
-func parseNode(node *html.Node, card *Card) {
-	// origin
-	ps := findNode(node, func(n *html.Node) bool {
-		if n.Type == html.ElementNode && n.Data == "p" {
-			for _, a := range n.Attr {
-				return strings.Contains(a.Val, "vGrnd")
+	if v.ConjugationURL != "" {
+		for _, card := range cards {
+			if card.Pos != PosVerb {
+				continue
+			}
+			conj, err := v.conjugate(ctx, card.Word())
+			if err != nil {
+				// the card is useful without the tables, so only canceled lookup fails
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				continue
 			}
+			card.Conjugation = conj
 		}
-		return false
-	})
+	}
 
-	textNodes := findNodes(ps, func(n *html.Node) bool {
-		return n.Type == html.TextNode
-	})
+	return cards, nil
+}
 
-	var o []string
-	for _, n := range textNodes {
-		if n.Data != "\n" {
-			o = append(o, strings.TrimSpace(strings.Trim(n.Data, "\n")))
-		}
+// fetch gets and parses the page, retrying with exponential backoff on 5xx and 429
+func (v VerbFormen) fetch(ctx context.Context, url string) (*html.Node, error) {
+	backoff := v.Backoff
+	if backoff <= 0 {
+		backoff = DefaultBackoff
 	}
 
-	// word forms
-	ps = findNode(node, func(n *html.Node) bool {
-		if n.Type == html.ElementNode && n.Data == "p" {
-			for _, a := range n.Attr {
-				return strings.Contains(a.Val, "vStm")
-			}
+	for attempt := 0; ; attempt++ {
+		node, err := v.get(ctx, url)
+		var se statusError
+		if err == nil || !errors.As(err, &se) || !se.temporary() || attempt >= v.Retries {
+			return node, err
 		}
-		return false
-	})
 
-	textNodes = findNodes(ps, func(n *html.Node) bool {
-		return n.Type == html.TextNode
-	})
+		wait := backoff << attempt
+		if se.retryAfter > wait {
+			wait = se.retryAfter
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func (v VerbFormen) get(ctx context.Context, url string) (*html.Node, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	r, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	if r.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if r.StatusCode != http.StatusOK {
+		// Retry-After in seconds, http-date is not used by the sites
+		secs, _ := strconv.Atoi(r.Header.Get("Retry-After"))
+		return nil, statusError{code: r.StatusCode, retryAfter: time.Duration(secs) * time.Second}
+	}
 
+	return html.Parse(r.Body)
+}
+
+// parseNode takes HTML node with `section` from verbformen.com and parses it into Card struct.
+// Verb, noun and adjective pages have the same layout: the word in `vGrnd`, forms in `vStm`
+// and the word info (part of speech, gender, declension) in `rInf`.
+func parseNode(node *html.Node, card *Card) {
+	// origin, with the article for nouns
+	var o []string
+	for _, n := range textNodes(findNode(node, withClass("vGrnd"))) {
+		if d := strings.TrimSpace(n.Data); d != "" {
+			o = append(o, d)
+		}
+	}
+
+	// word forms, endings are green and changed stems are blue
 	var fs []*Syllable
-	// todo: switch
-	for _, n := range textNodes {
+	for _, n := range textNodes(findNode(node, withClass("vStm"))) {
 		d := strings.ReplaceAll(n.Data, "\n", " ")
-		if d == "" || d == " " {
+		if strings.TrimSpace(d) == "" {
 			continue
 		}
 
-		// neutral
-		if n.Parent.Data == "p" || n.Parent.Data == "b" {
+		switch n.Parent.Data {
+		case "i":
+			fs = append(fs, &Syllable{d, Green})
+		case "u":
+			fs = append(fs, &Syllable{d, Blue})
+		default:
 			fs = append(fs, &Syllable{d, Default})
 		}
+	}
+	// line breaks around the forms are not part of them
+	if len(fs) > 0 {
+		fs[0].Val = strings.TrimLeft(fs[0].Val, " ")
+		fs[len(fs)-1].Val = strings.TrimRight(fs[len(fs)-1].Val, " ")
+	}
 
-		// green
-		if n.Parent.Data == "i" {
-			fs = append(fs, &Syllable{d, Green})
+	// translations, a span per language, translations of examples are not ones of the word
+	spans := findNodes(node, func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == "span" && langOf(n) != "" && langOf(n) != "de" &&
+			!inside(n, withClass("vBsp"))
+	})
+
+	card.Origin = o
+	card.Translations = map[string][]store.Sense{}
+	for _, span := range spans {
+		lang := langOf(span)
+		if _, ok := card.Translations[lang]; ok {
+			continue
 		}
 
-		// blue
-		if n.Parent.Data == "u" {
-			fs = append(fs, &Syllable{d, Blue})
+		var data string
+		for _, n := range textNodes(span) {
+			if n.Data != "\n" {
+				data = n.Data
+			}
+		}
+
+		// translations of the same meaning are separated by commas or new lines, meanings – by semicolons
+		if ss := store.ParseSenses(strings.ReplaceAll(data, "\n", ",")); len(ss) > 0 {
+			card.Translations[lang] = ss
 		}
 	}
+	card.Forms = fs
+	card.Examples = parseExamples(node)
 
-	// translations
-	ts := findNode(node, func(n *html.Node) bool {
-		if n.Type == html.ElementNode && n.Data == "span" {
-			for _, a := range n.Attr {
-				return a.Key == "lang" && a.Val == "en"
+	// typed forms by part of speech
+	var info []string
+	for _, n := range textNodes(findNode(node, withClass("rInf"))) {
+		info = append(info, n.Data)
+	}
+	forms := splitForms(fs)
+	card.Pos = partOfSpeech(strings.Join(info, ""), o, forms)
+	switch card.Pos {
+	case PosNoun:
+		card.Noun = parseNoun(strings.Join(info, ""), o, forms)
+	case PosAdjective:
+		card.Adjective = parseAdjective(forms)
+	}
+}
+
+// withClass returns predicate of elements with the class
+func withClass(class string) func(n *html.Node) bool {
+	return func(n *html.Node) bool {
+		if n.Type != html.ElementNode {
+			return false
+		}
+		for _, a := range n.Attr {
+			if a.Key == "class" {
+				for _, c := range strings.Fields(a.Val) {
+					if c == class {
+						return true
+					}
+				}
 			}
 		}
 		return false
-	})
+	}
+}
+
+// inside checks if any of the node ancestors matches the predicate
+func inside(n *html.Node, pred func(n *html.Node) bool) bool {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if pred(p) {
+			return true
+		}
+	}
+	return false
+}
 
-	textNodes = findNodes(ts, func(n *html.Node) bool {
+// textNodes returns all text nodes under the node
+func textNodes(n *html.Node) []*html.Node {
+	return findNodes(n, func(n *html.Node) bool {
 		return n.Type == html.TextNode
 	})
+}
 
-	var data string
-	for _, n := range textNodes {
-		if n.Data != "\n" {
-			data = n.Data
+// langOf returns lang attribute of the node
+func langOf(n *html.Node) string {
+	for _, a := range n.Attr {
+		if a.Key == "lang" {
+			return a.Val
 		}
 	}
-
-	var t []string //nolint:prealloc // slice size is unknown
-	for _, w := range strings.Split(data, "\n") {
-		t = append(t, strings.TrimSuffix(strings.TrimSpace(w), ","))
-	}
-
-	card.Origin = o
-	card.Translation = t
-	card.Forms = fs
+	return ""
 }
 
 func findNodes(n *html.Node, pred func(node *html.Node) bool) []*html.Node {
+	if n == nil {
+		return nil
+	}
 	var ns []*html.Node
 
 	if pred(n) {
@@ -0,0 +1,312 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/egregors/karten/pkg/store"
+)
+
+// pageServer serves hand-written pages of testdata, see testdata/README.md: /search/<word> is <word>.html
+// and /conjugation/<word> is <word>_conjugation.html, 404 if there is no such file. Words are lowercased as the site
+// doesn't care about case.
+func pageServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/search/")
+		if c := strings.TrimPrefix(r.URL.Path, "/conjugation/"); c != r.URL.Path {
+			name = c + "_conjugation"
+		}
+		data, err := os.ReadFile(filepath.Join("testdata", strings.ToLower(name)+".html"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestVerbFormen_Lookup(t *testing.T) {
+	srv := pageServer(t)
+	v := VerbFormen{URL: srv.URL + "/search/", Client: srv.Client()}
+
+	card, err := v.Lookup(context.Background(), "gehen")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := card.Word(); got != "gehen" {
+		t.Errorf("got origin %q", got)
+	}
+	if got := store.FormatSenses(card.Senses); got != "go, walk; work, function" {
+		t.Errorf("got senses %q", got)
+	}
+	if card.Lang != "en" || len(card.Translations) != 2 {
+		t.Errorf("got %q senses of translations %v", card.Lang, card.Translations)
+	}
+	if got := splitForms(card.Forms); !reflect.DeepEqual(got, []string{"geht", "ging", "ist gegangen"}) {
+		t.Errorf("got forms %q", got)
+	}
+	if card.Pos != PosVerb {
+		t.Errorf("got part of speech %q", card.Pos)
+	}
+	if card.Conjugation != nil {
+		t.Errorf("conjugation is fetched without ConjugationURL: %v", card.Conjugation)
+	}
+
+	wantExamples := []store.Example{
+		{
+			Text:         "Ich ging gestern nach Hause.",
+			Word:         "ging",
+			Translations: map[string]string{"en": "I went home yesterday.", "ru": "Я вчера пошёл домой."},
+		},
+		{Text: "Wie geht es dir?", Word: "geht", Translations: map[string]string{"en": "How are you?"}},
+	}
+	if !reflect.DeepEqual(card.Examples, wantExamples) {
+		t.Errorf("got examples %+v", card.Examples)
+	}
+}
+
+func TestVerbFormen_LookupLanguages(t *testing.T) {
+	srv := pageServer(t)
+
+	tbl := []struct {
+		langs        []string
+		lang, senses string
+	}{
+		{nil, "en", "go, walk; work, function"},
+		{[]string{"ru"}, "ru", "идти, ходить; работать"},
+		{[]string{"es", "ru"}, "ru", "идти, ходить; работать"},
+		{[]string{"es"}, "en", "go, walk; work, function"},
+		// German "translation" is the word itself
+		{[]string{"de"}, "en", "go, walk; work, function"},
+	}
+
+	for _, tt := range tbl {
+		t.Run(strings.Join(tt.langs, ","), func(t *testing.T) {
+			v := VerbFormen{URL: srv.URL + "/search/", Client: srv.Client(), Languages: tt.langs}
+			card, err := v.Lookup(context.Background(), "gehen")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if card.Lang != tt.lang || store.FormatSenses(card.Senses) != tt.senses {
+				t.Errorf("got %q senses %q, want %q %q", card.Lang, store.FormatSenses(card.Senses), tt.lang, tt.senses)
+			}
+		})
+	}
+}
+
+func TestVerbFormen_LookupConjugation(t *testing.T) {
+	srv := pageServer(t)
+	v := VerbFormen{URL: srv.URL + "/search/", ConjugationURL: srv.URL + "/conjugation/", Client: srv.Client()}
+
+	card, err := v.Lookup(context.Background(), "gehen")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := store.Conjugation{
+		{Name: "Präsens", Forms: []string{"gehe", "gehst", "geht", "gehen", "geht", "gehen"}},
+		{Name: "Präteritum", Forms: []string{"ging", "gingst", "ging", "gingen", "gingt", "gingen"}},
+		{Name: "Konjunktiv I", Forms: []string{"gehe", "gehest", "gehe", "gehen", "gehet", "gehen"}},
+		{Name: "Konjunktiv II", Forms: []string{"ginge", "gingest", "ginge", "gingen", "ginget", "gingen"}},
+	}
+	if !reflect.DeepEqual(card.Conjugation, want) {
+		t.Errorf("got conjugation %v", card.Conjugation)
+	}
+}
+
+func TestVerbFormen_LookupNotFound(t *testing.T) {
+	srv := pageServer(t)
+	v := VerbFormen{URL: srv.URL + "/search/", Client: srv.Client()}
+
+	// 404 and a page without cards
+	for _, w := range []string{"xyz", "empty"} {
+		if _, err := v.Lookup(context.Background(), w); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s: got %v, want %v", w, err, ErrNotFound)
+		}
+	}
+}
+
+// flakyServer responds with the statuses in order, and with the gehen page after them
+func flakyServer(t *testing.T, header http.Header, statuses ...int) (srv *httptest.Server, calls func() int) {
+	t.Helper()
+	page, err := os.ReadFile(filepath.Join("testdata", "gehen.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	n := 0
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		i := n
+		n++
+		mu.Unlock()
+
+		if i < len(statuses) {
+			for k, vs := range header {
+				w.Header()[k] = vs
+			}
+			w.WriteHeader(statuses[i])
+			return
+		}
+		_, _ = w.Write(page)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return n
+	}
+}
+
+func TestVerbFormen_Retry(t *testing.T) {
+	tbl := []struct {
+		name     string
+		statuses []int
+		retries  int
+		calls    int
+		code     int
+	}{
+		{"no errors", nil, 2, 1, 0},
+		{"5xx", []int{http.StatusServiceUnavailable, http.StatusBadGateway}, 2, 3, 0},
+		{"429", []int{http.StatusTooManyRequests}, 1, 2, 0},
+		{"out of retries", []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}, 1, 2, http.StatusTooManyRequests},
+		{"no retries", []int{http.StatusInternalServerError}, 0, 1, http.StatusInternalServerError},
+		{"4xx is not retried", []int{http.StatusForbidden}, 2, 1, http.StatusForbidden},
+	}
+
+	for _, tt := range tbl {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := flakyServer(t, nil, tt.statuses...)
+			v := VerbFormen{URL: srv.URL + "/", Client: srv.Client(), Retries: tt.retries, Backoff: time.Millisecond}
+
+			card, err := v.Lookup(context.Background(), "gehen")
+			if tt.code == 0 {
+				if err != nil || card.Word() != "gehen" {
+					t.Errorf("got %v, %v", card, err)
+				}
+			} else {
+				var se statusError
+				if !errors.As(err, &se) || se.code != tt.code {
+					t.Errorf("got %v, want status %d", err, tt.code)
+				}
+			}
+			if got := calls(); got != tt.calls {
+				t.Errorf("got %d requests, want %d", got, tt.calls)
+			}
+		})
+	}
+}
+
+func TestVerbFormen_RetryAfter(t *testing.T) {
+	srv, calls := flakyServer(t, http.Header{"Retry-After": {"1"}}, http.StatusTooManyRequests)
+	v := VerbFormen{URL: srv.URL + "/", Client: srv.Client(), Retries: 1, Backoff: time.Millisecond}
+
+	start := time.Now()
+	if _, err := v.Lookup(context.Background(), "gehen"); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < time.Second {
+		t.Errorf("retried in %v, before Retry-After", d)
+	}
+	if calls() != 2 {
+		t.Errorf("got %d requests, want 2", calls())
+	}
+}
+
+func TestVerbFormen_Cancel(t *testing.T) {
+	t.Run("request", func(t *testing.T) {
+		// the server never responds until the request is canceled
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer srv.Close()
+		v := VerbFormen{URL: srv.URL + "/", Client: srv.Client()}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		if _, err := v.Lookup(ctx, "gehen"); !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v, want %v", err, context.Canceled)
+		}
+	})
+
+	t.Run("backoff", func(t *testing.T) {
+		srv, calls := flakyServer(t, nil, http.StatusServiceUnavailable)
+		v := VerbFormen{URL: srv.URL + "/", Client: srv.Client(), Retries: 1, Backoff: time.Hour}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		if _, err := v.Lookup(ctx, "gehen"); !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v, want %v", err, context.Canceled)
+		}
+		if calls() != 1 {
+			t.Errorf("got %d requests, want 1", calls())
+		}
+	})
+}
+
+func TestVerbFormen_UserAgent(t *testing.T) {
+	var mu sync.Mutex
+	var agents []string
+	pages := pageServer(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		agents = append(agents, r.UserAgent())
+		mu.Unlock()
+		pages.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	v := VerbFormen{URL: srv.URL + "/search/", ConjugationURL: srv.URL + "/conjugation/", Client: srv.Client()}
+	if _, err := v.Lookup(context.Background(), "gehen"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the search page and the conjugation one
+	if !reflect.DeepEqual(agents, []string{UserAgent, UserAgent}) {
+		t.Errorf("got User-Agent %q, want %q", agents, UserAgent)
+	}
+}
+
+func TestCard_Apply(t *testing.T) {
+	card := Card{
+		Origin: []string{"gehen"},
+		Translations: map[string][]store.Sense{
+			"en": store.ParseSenses("go, walk"),
+			"ru": store.ParseSenses("идти"),
+		},
+	}
+	card.Prefer("ru")
+
+	w := store.NewWord("gehen")
+	card.Apply(w)
+	if got := w.Translation(); got != "идти" {
+		t.Errorf("got senses %q", got)
+	}
+	// the preferred language is kept too, so the word is shown in it whatever the senses are
+	for _, l := range []string{"ru", "en"} {
+		if got := w.TranslationIn(l); got != store.FormatSenses(card.Translations[l]) {
+			t.Errorf("got %q in %s", got, l)
+		}
+	}
+
+	// cards without language have no translations
+	(&Card{Origin: []string{"da"}, Senses: store.ParseSenses("there")}).Apply(w)
+	if w.Translations != nil {
+		t.Errorf("got translations %v", w.Translations)
+	}
+}
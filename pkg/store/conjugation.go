@@ -0,0 +1,78 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Persons are personal pronouns of conjugated forms, in order of Tense forms
+var Persons = []string{"ich", "du", "er", "wir", "ihr", "sie"}
+
+// Tense is conjugated forms of a verb in one tense, a form per person of Persons
+type Tense struct {
+	Name  string   `json:"name"`
+	Forms []string `json:"forms"`
+}
+
+// Conjugation is tenses of a verb, like Präsens, Präteritum, Konjunktiv I and II
+type Conjugation []Tense
+
+// Grid renders tenses as columns with a row per person, rows are separated by new lines
+func (c Conjugation) Grid() string {
+	if len(c) == 0 {
+		return ""
+	}
+
+	// column widths in runes, fmt pads by runes too
+	widths := make([]int, len(c))
+	for i, t := range c {
+		widths[i] = utf8.RuneCountInString(t.Name)
+		for _, f := range t.Forms {
+			if n := utf8.RuneCountInString(f); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	row := func(first string, cell func(t Tense) string) string {
+		cells := []string{fmt.Sprintf("%-4s", first)}
+		for i, t := range c {
+			cells = append(cells, fmt.Sprintf("%-*s", widths[i], cell(t)))
+		}
+		return strings.TrimRight(strings.Join(cells, "  "), " ")
+	}
+
+	lines := []string{row("", func(t Tense) string { return t.Name })}
+	for p, person := range Persons {
+		lines = append(lines, row(person, func(t Tense) string {
+			if p < len(t.Forms) {
+				return t.Forms[p]
+			}
+			return ""
+		}))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// encodeConjugation serializes conjugation for persistent store
+func encodeConjugation(c Conjugation) string {
+	if len(c) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(c)
+	return string(b)
+}
+
+// decodeConjugation deserializes conjugation, malformed one is dropped
+func decodeConjugation(s string) Conjugation {
+	if s == "" {
+		return nil
+	}
+	var c Conjugation
+	if err := json.Unmarshal([]byte(s), &c); err != nil {
+		return nil
+	}
+	return c
+}
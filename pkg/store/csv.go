@@ -2,21 +2,20 @@ package store
 
 import (
 	"container/heap"
-	"encoding/csv"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
-const (
-	csvOrigin int = iota
-	csvTranslation
-	csvLastSeenAt
-	csvScore
-	csvMeta
-)
+const tagsSep = ","
+
+// ErrNotFound means there is no such Word in the store
+var ErrNotFound = errors.New("word not found")
 
 // CSV is .csv store backend for words. Compliantly simple. Read full file from disk.
 // Save method will override whole file.
@@ -24,15 +23,54 @@ type CSV struct {
 	Path string
 }
 
-// NewCSV open creates new CSV store. Creates a new CSV file, if it does not exist.
+// NewCSV open creates new CSV store. Creates a new CSV file, if it does not exist,
+// and migrates the file made by previous versions.
 func NewCSV(path string) (*CSV, error) {
-	err := getPath(path)
+	c, err := OpenCSV(path)
 	if err != nil {
+		return nil, err
+	}
+	if err := c.migrate(); err != nil {
+		return nil, fmt.Errorf("can't migrate CSV file: %w", err)
+	}
+	return c, nil
+}
+
+// OpenCSV opens CSV store without migration, the file is kept as is to be checked by Check.
+// Creates a new CSV file, if it does not exist.
+func OpenCSV(path string) (*CSV, error) {
+	if err := getPath(path); err != nil {
 		return nil, fmt.Errorf("can't make CSV file: %w", err)
 	}
 	return &CSV{Path: path}, nil
 }
 
+// migrate upgrades CSV file made by previous versions into the current schema.
+// The original file is kept as a backup next to it.
+func (c CSV) migrate() error {
+	t, err := c.readTable()
+	if err != nil {
+		return err
+	}
+	if t.version == SchemaVersion {
+		return nil
+	}
+	if t.version > SchemaVersion {
+		return fmt.Errorf("schema v%d, supported v%d: %w", t.version, SchemaVersion, ErrNewerSchema)
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", c.Path, t.version)
+	if err := copyFile(c.Path, backup); err != nil {
+		return fmt.Errorf("can't backup file before migration: %w", err)
+	}
+
+	ws, err := c.loadAll()
+	if err != nil {
+		return err
+	}
+	return c.saveAll(ws)
+}
+
 func getPath(path string) error {
 	if isFileExist(path) {
 		return nil
@@ -52,139 +90,269 @@ func getPath(path string) error {
 	return nil
 }
 
-// loadAll loads all word from CSV file in random order
-func (c CSV) loadAll() (ws Words, err error) {
-	f, err := os.Open(filepath.Clean(c.Path))
+// readTable reads raw CSV file content
+func (c CSV) readTable() (*table, error) {
+	data, err := os.ReadFile(filepath.Clean(c.Path))
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = f.Close() }()
-
-	r := csv.NewReader(f)
-	r.Comma = ';'
+	return readTable(data)
+}
 
-	data, err := r.ReadAll()
+// loadAll loads all word from CSV file in random order
+func (c CSV) loadAll() (ws Words, err error) {
+	t, err := c.readTable()
 	if err != nil {
 		return nil, err
 	}
+	if err := t.migrate(); err != nil {
+		return nil, err
+	}
 
-	for _, row := range data[1:] {
-		t, err := time.Parse(time.RFC3339, row[csvLastSeenAt])
-		if err != nil {
-			t = time.Time{}
-		}
-
-		score, err := strconv.Atoi(row[csvScore])
-		if err != nil {
-			score = 0
-		}
-
-		w := &Word{
-			Origin:      row[csvOrigin],
-			Translation: row[csvTranslation],
-			LastSeenAt:  t,
-			Score:       score,
-			Meta:        row[csvMeta],
-		}
-
-		if err != nil {
-			fmt.Printf("can't make a word from data: %v", err)
-			continue
-		}
-		ws = append(ws, w)
+	for _, r := range t.records {
+		ws = append(ws, fromRecord(r))
 	}
 	return ws, nil
 }
 
 // saveAll saves all words into CSV file
 func (c CSV) saveAll(ws Words) error {
-	f, err := os.OpenFile(filepath.Clean(c.Path), os.O_WRONLY|os.O_CREATE, 0o600)
+	f, err := os.OpenFile(filepath.Clean(c.Path), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = f.Close() }()
-	w := csv.NewWriter(f)
-	w.Comma = ';'
-	// hint: schema
-	titles := []string{"origin", "translation", "last_seen_at", "score", "meta"}
-	err = w.Write(titles)
+
+	rows := make([][]string, len(ws))
+	for i, word := range ws {
+		rows[i] = toRow(*word)
+	}
+	return writeTable(f, rows)
+}
+
+// AddWord adds new word in words collection and saves on disc. Meanings of a word which is already
+// in the store (the origin is matched as by Get) are added to the stored one.
+func (c CSV) AddWord(w *Word) error {
+	ws, err := c.loadAll()
 	if err != nil {
 		return err
 	}
 	for _, word := range ws {
-		err = w.Write(toRow(*word))
-		if err != nil {
-			return err
+		if dedupKey(word.Origin) == dedupKey(w.Origin) {
+			word.AddMeanings(w)
+			return c.saveAll(ws)
 		}
 	}
-	w.Flush()
-	return nil
+	return c.saveAll(append(ws, w))
 }
 
-// AddWord adds new word in words collection and saves on disc
-func (c CSV) AddWord(w *Word) error {
-	// todo: reduplication
+// GetWords loads words matching the Filter and put in into a heap according the score
+func (c CSV) GetWords(n int, f Filter) (*Words, error) {
 	ws, err := c.loadAll()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	cut := &Words{}
+	for i := 0; cut.Len() < n && i < len(ws); i++ {
+		if f.Match(ws[i]) {
+			heap.Push(cut, ws[i])
+		}
 	}
-	ws = append(ws, w)
-	err = c.saveAll(ws)
+
+	return cut, nil
+}
+
+// Find returns all words matching the Filter in the store order
+func (c CSV) Find(f Filter) (Words, error) {
+	ws, err := c.loadAll()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+
+	var res Words
+	for _, w := range ws {
+		if f.Match(w) {
+			res = append(res, w)
+		}
+	}
+
+	return res, nil
 }
 
-// GetWords loads words and put in into a heap according the score
-func (c CSV) GetWords(n int) (*Words, error) {
+// Tags returns all tags used in the store in alphabetical order
+func (c CSV) Tags() ([]string, error) {
 	ws, err := c.loadAll()
 	if err != nil {
 		return nil, err
 	}
 
-	cut := &Words{}
-	for i := 0; i < n && i < len(ws); i++ {
-		heap.Push(cut, ws[i])
+	seen := map[string]bool{}
+	var tags []string
+	for _, w := range ws {
+		for _, t := range w.Tags {
+			if !seen[t] {
+				seen[t] = true
+				tags = append(tags, t)
+			}
+		}
 	}
+	sort.Strings(tags)
 
-	return cut, nil
+	return tags, nil
+}
+
+// AddWords adds new words in words collection skipping the ones already exist.
+// Returns number of added words.
+func (c CSV) AddWords(ws ...*Word) (int, error) {
+	all, err := c.loadAll()
+	if err != nil {
+		return 0, err
+	}
+
+	known := map[string]bool{}
+	for _, w := range all {
+		known[dedupKey(w.Origin)] = true
+	}
+
+	added := 0
+	for _, w := range ws {
+		key := dedupKey(w.Origin)
+		if key == "" || known[key] {
+			continue
+		}
+		known[key] = true
+		all = append(all, w)
+		added++
+	}
+
+	return added, c.saveAll(all)
 }
 
-// Save saves Word into CSV file
+// Get returns Word by origin, case matters: Essen and essen are different words
+func (c CSV) Get(origin string) (*Word, error) {
+	ws, err := c.loadAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range ws {
+		if dedupKey(w.Origin) == dedupKey(origin) {
+			return w, nil
+		}
+	}
+	return nil, fmt.Errorf("%q: %w", origin, ErrNotFound)
+}
+
+// Delete removes Word by origin, case matters
+func (c CSV) Delete(origin string) error {
+	ws, err := c.loadAll()
+	if err != nil {
+		return err
+	}
+	for i, w := range ws {
+		if dedupKey(w.Origin) == dedupKey(origin) {
+			return c.saveAll(append(ws[:i], ws[i+1:]...))
+		}
+	}
+	return fmt.Errorf("%q: %w", origin, ErrNotFound)
+}
+
+// Save saves Word into CSV file, the Word is found by origin as by Get
 func (c CSV) Save(w *Word) error {
 	ws, err := c.loadAll()
 	if err != nil {
 		return err
 	}
 	for i, word := range ws {
-		if word.Origin == w.Origin {
+		if dedupKey(word.Origin) == dedupKey(w.Origin) {
 			ws[i] = w
-			break
+			return c.saveAll(ws)
 		}
 	}
-	err = c.saveAll(ws)
+	return fmt.Errorf("%q: %w", w.Origin, ErrNotFound)
+}
+
+// fromRecord perform deserialization from CSV record to Word.
+// Malformed values are replaced by zero values.
+func fromRecord(r record) *Word {
+	lastSeen, err := time.Parse(time.RFC3339, r[colLastSeenAt])
 	if err != nil {
-		return err
+		lastSeen = time.Time{}
+	}
+
+	added, err := time.Parse(time.RFC3339, r[colAddedAt])
+	if err != nil {
+		added = time.Time{}
+	}
+
+	score, err := strconv.Atoi(r[colScore])
+	if err != nil {
+		score = 0
+	}
+
+	return &Word{
+		Origin:     r[colOrigin],
+		Senses:     decodeSenses(r[colSenses]),
+		LastSeenAt: lastSeen,
+		Score:      score,
+		Meta:       r[colMeta],
+		Tags:       splitTags(r[colTags]),
+		Deck:       r[colDeck],
+		AddedAt:    added,
+		Suspended:  r[colSuspended] == "true",
+		Example:    r[colExample],
+		Mnemonic:   r[colMnemonic],
+		Notes:      r[colNotes],
+
+		Translations: decodeTranslations(r[colTranslations]),
+		Conjugation:  decodeConjugation(r[colConjugation]),
+		Examples:     decodeExamples(r[colExamples]),
 	}
-	return nil
 }
 
-// toRow perform serialization from Word to CSV row.
+// toRow perform serialization from Word to CSV row in the titles order.
+//
 //	 Schema:
 //	 	origin 				:: string
-//		translation 		:: string
+//		senses 				:: string[JSON]
 //		last_seen_at 		:: string[time.RFC3339]
 //		score 				:: int
 //		meta 				:: string
+//		tags 				:: string[comma separated]
+//		deck 				:: string
+//		added_at 			:: string[time.RFC3339]
+//		suspended 			:: bool
+//		example 			:: string
+//		mnemonic 			:: string
+//		notes 				:: string
+//		translations 		:: string[JSON]
+//		conjugation 		:: string[JSON]
+//		examples 			:: string[JSON]
 func toRow(w Word) []string {
 	return []string{
 		w.Origin,
-		w.Translation,
+		encodeSenses(w.Senses),
 		w.LastSeenAt.Format(time.RFC3339),
 		strconv.Itoa(w.Score),
 		w.Meta,
+		strings.Join(w.Tags, tagsSep),
+		w.Deck,
+		w.AddedAt.Format(time.RFC3339),
+		strconv.FormatBool(w.Suspended),
+		w.Example,
+		w.Mnemonic,
+		w.Notes,
+		encodeTranslations(w.Translations),
+		encodeConjugation(w.Conjugation),
+		encodeExamples(w.Examples),
+	}
+}
+
+func splitTags(s string) []string {
+	if s == "" {
+		return nil
 	}
+	return strings.Split(s, tagsSep)
 }
 
 func isFileExist(path string) bool {
@@ -194,6 +362,14 @@ func isFileExist(path string) bool {
 	return true
 }
 
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(filepath.Clean(src))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Clean(dst), data, 0o600)
+}
+
 func createFile(path string) error {
 	f, err := os.Create(filepath.Clean(path))
 	if err != nil {
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCSV_CaseSensitiveOrigins(t *testing.T) {
+	c, err := NewCSV(filepath.Join(t.TempDir(), "words.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	essen, eat := NewWord("Essen"), NewWord("essen")
+	essen.SetTranslation("food")
+	eat.SetTranslation("to eat")
+	n, err := c.AddWords(essen, eat, NewWord(" Essen "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("added %d words, want 2", n)
+	}
+
+	w, err := c.Get("essen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Translation() != "to eat" {
+		t.Errorf("got %q of essen", w.Translation())
+	}
+
+	// Save updates the word Get returns, not the one differs in case
+	w.SetTranslation("eat")
+	if err := c.Save(w); err != nil {
+		t.Fatal(err)
+	}
+	if w, _ := c.Get("Essen"); w.Translation() != "food" {
+		t.Errorf("Essen is changed: %q", w.Translation())
+	}
+	if w, _ := c.Get("essen"); w.Translation() != "eat" {
+		t.Errorf("essen is not saved: %q", w.Translation())
+	}
+
+	// a word missing in the store is not saved silently
+	if err := c.Save(NewWord("ESSEN")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want %v", err, ErrNotFound)
+	}
+	if err := c.Delete("ESSEN"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want %v", err, ErrNotFound)
+	}
+	if err := c.Delete("Essen"); err != nil {
+		t.Fatal(err)
+	}
+	ws, err := c.Find(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ws) != 1 || ws[0].Origin != "essen" {
+		t.Errorf("got %v after delete", ws)
+	}
+}
+
+func TestCSV_AddWordTwice(t *testing.T) {
+	c, err := NewCSV(filepath.Join(t.TempDir(), "words.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bench := NewWord("die Bank")
+	bench.SetTranslation("bench")
+	bench.Translations = map[string][]Sense{"en": bench.Senses}
+	bench.Meta = "die Bank\nBank · Bänke"
+	bench.Tags = []string{"nouns"}
+	if err := c.AddWord(bench); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := c.Get("die Bank")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored.IncScore()
+	if err := c.Save(stored); err != nil {
+		t.Fatal(err)
+	}
+
+	bank := NewWord("die Bank")
+	bank.SetTranslation("bank")
+	bank.Translations = map[string][]Sense{"en": bank.Senses, "ru": ParseSenses("банк")}
+	bank.Meta = "die Bank\nBank · Banken"
+	bank.Tags = []string{"A1"}
+	// the same meaning is not repeated
+	for _, w := range []*Word{bank, bench} {
+		if err := c.AddWord(w); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ws, err := c.Find(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ws) != 1 {
+		t.Fatalf("got %d words, want 1", len(ws))
+	}
+	w := ws[0]
+	if w.Translation() != "bench; bank" || w.TranslationIn("en") != "bench; bank" || w.TranslationIn("ru") != "банк" {
+		t.Errorf("got senses %q, en %q, ru %q", w.Translation(), w.TranslationIn("en"), w.TranslationIn("ru"))
+	}
+	if w.Meta != "die Bank\nBank · Bänke\nBank · Banken" {
+		t.Errorf("got meta %q", w.Meta)
+	}
+	if strings.Join(w.Tags, ",") != "nouns,A1" {
+		t.Errorf("got tags %v", w.Tags)
+	}
+	if w.Score != stored.Score {
+		t.Errorf("progress is lost: score %d, want %d", w.Score, stored.Score)
+	}
+}
@@ -0,0 +1,339 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Problem kinds
+const (
+	ProblemMalformed = "malformed"
+	ProblemDuplicate = "duplicate"
+	ProblemOrphaned  = "orphaned"
+	ProblemScore     = "score"
+	ProblemGarbage   = "garbage"
+)
+
+// Problem is an integrity issue found in the store
+type Problem struct {
+	// File is a path of the file with the Problem
+	File string
+	// Line is line number in the File
+	Line   int
+	Origin string
+	Kind   string
+	Msg    string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s:%d: [%s] %q: %s", p.File, p.Line, p.Kind, p.Origin, p.Msg)
+}
+
+// Check scans words store and review log (could be nil) and reports all problems found.
+// The store file should not be migrated before, migration resets malformed values.
+func Check(c *CSV, l *ReviewLog) ([]Problem, error) {
+	t, err := c.readTable()
+	if err != nil {
+		return nil, err
+	}
+	cols := len(t.header)
+	// columns added by migration are empty, only ones of the file are checked
+	has := map[string]bool{}
+	for _, h := range t.header {
+		has[h] = true
+	}
+	if err := t.migrate(); err != nil {
+		return nil, err
+	}
+
+	var ps []Problem
+	report := func(i int, kind, msg string) {
+		ps = append(ps, Problem{
+			File:   c.Path,
+			Line:   t.lines[i],
+			Origin: t.records[i][colOrigin],
+			Kind:   kind,
+			Msg:    msg,
+		})
+	}
+
+	seen := map[string]int{}
+	for i, r := range t.records {
+		if t.widths[i] != cols {
+			report(i, ProblemMalformed, fmt.Sprintf("%d columns instead of %d", t.widths[i], cols))
+		}
+		if strings.TrimSpace(r[colOrigin]) == "" {
+			report(i, ProblemMalformed, "empty origin")
+		}
+		for _, col := range []string{colLastSeenAt, colAddedAt} {
+			if _, err := time.Parse(time.RFC3339, r[col]); has[col] && err != nil {
+				report(i, ProblemMalformed, fmt.Sprintf("bad %s %q", col, r[col]))
+			}
+		}
+		if _, err := strconv.ParseBool(r[colSuspended]); has[colSuspended] && err != nil {
+			report(i, ProblemMalformed, fmt.Sprintf("bad %s %q", colSuspended, r[colSuspended]))
+		}
+		if has[colSenses] && r[colSenses] != "" && !json.Valid([]byte(r[colSenses])) {
+			report(i, ProblemMalformed, fmt.Sprintf("bad %s %q", colSenses, r[colSenses]))
+		}
+
+		score, err := strconv.Atoi(r[colScore])
+		switch {
+		case err != nil:
+			report(i, ProblemMalformed, fmt.Sprintf("bad %s %q", colScore, r[colScore]))
+		case score < MinScore || score > MaxScore:
+			report(i, ProblemScore, fmt.Sprintf("score %d is out of [%d, %d]", score, MinScore, MaxScore))
+		}
+
+		for _, col := range titles {
+			// meta is colored on purpose
+			if col != colMeta && columnHasGarbage(col, r[col]) {
+				report(i, ProblemGarbage, fmt.Sprintf("escape codes or control symbols in %s", col))
+			}
+		}
+
+		key := dedupKey(r[colOrigin])
+		if first, ok := seen[key]; ok {
+			report(i, ProblemDuplicate, fmt.Sprintf("duplicate of line %d", t.lines[first]))
+		} else {
+			seen[key] = i
+		}
+	}
+
+	if l == nil {
+		return ps, nil
+	}
+
+	rows, err := l.readRows()
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		r, err := reviewFromRow(row.fields)
+		p := Problem{File: l.Path, Line: row.line, Origin: r.Origin}
+		switch {
+		case err != nil:
+			p.Kind, p.Msg = ProblemMalformed, err.Error()
+		case !hasKey(seen, dedupKey(r.Origin)):
+			p.Kind, p.Msg = ProblemOrphaned, "review of unknown word"
+		default:
+			continue
+		}
+		ps = append(ps, p)
+	}
+
+	return ps, nil
+}
+
+// Repair fixes all problems Check could find: malformed values are reset, scores are clamped,
+// garbage is stripped, duplicates are merged, and orphaned or malformed reviews are dropped.
+// Both files are backed up before, backup paths are returned.
+func Repair(c *CSV, l *ReviewLog) (backups []string, err error) {
+	stamp := time.Now().Format("20060102-150405")
+
+	backup := fmt.Sprintf("%s.%s.bak", c.Path, stamp)
+	if err := copyFile(c.Path, backup); err != nil {
+		return nil, fmt.Errorf("can't backup %s: %w", c.Path, err)
+	}
+	backups = append(backups, backup)
+
+	ws, err := c.loadAll()
+	if err != nil {
+		return backups, err
+	}
+
+	var fixed Words
+	byKey := map[string]*Word{}
+	for _, w := range ws {
+		repairWord(w)
+		if w.Origin == "" {
+			continue
+		}
+
+		key := dedupKey(w.Origin)
+		if orig, ok := byKey[key]; ok {
+			mergeWords(orig, w)
+			continue
+		}
+		byKey[key] = w
+		fixed = append(fixed, w)
+	}
+
+	if err := c.saveAll(fixed); err != nil {
+		return backups, err
+	}
+
+	if l == nil {
+		return backups, nil
+	}
+
+	backup = fmt.Sprintf("%s.%s.bak", l.Path, stamp)
+	if err := copyFile(l.Path, backup); err != nil {
+		return backups, fmt.Errorf("can't backup %s: %w", l.Path, err)
+	}
+	backups = append(backups, backup)
+
+	// All skips malformed rows already
+	rs, err := l.All()
+	if err != nil {
+		return backups, err
+	}
+	var kept []Review
+	for _, r := range rs {
+		if hasKey(byKey, dedupKey(r.Origin)) {
+			kept = append(kept, r)
+		}
+	}
+
+	return backups, l.saveAll(kept)
+}
+
+// repairWord clamps score and strips garbage from the Word fields
+func repairWord(w *Word) {
+	if w.Score < MinScore {
+		w.Score = MinScore
+	}
+	if w.Score > MaxScore {
+		w.Score = MaxScore
+	}
+
+	w.Origin = stripGarbage(w.Origin)
+	w.Deck = stripGarbage(w.Deck)
+	w.Example = stripGarbage(w.Example)
+	w.Mnemonic = stripGarbage(w.Mnemonic)
+	w.Notes = stripGarbage(w.Notes)
+	for i := range w.Tags {
+		w.Tags[i] = stripGarbage(w.Tags[i])
+	}
+	repairSenses(w.Senses)
+	for _, ss := range w.Translations {
+		repairSenses(ss)
+	}
+	for i := range w.Examples {
+		e := &w.Examples[i]
+		e.Text = stripGarbage(e.Text)
+		e.Word = stripGarbage(e.Word)
+		for l, t := range e.Translations {
+			e.Translations[l] = stripGarbage(t)
+		}
+	}
+	for i := range w.Conjugation {
+		w.Conjugation[i].Name = stripGarbage(w.Conjugation[i].Name)
+		for j := range w.Conjugation[i].Forms {
+			w.Conjugation[i].Forms[j] = stripGarbage(w.Conjugation[i].Forms[j])
+		}
+	}
+}
+
+func repairSenses(ss []Sense) {
+	for i := range ss {
+		ss[i].Example = stripGarbage(ss[i].Example)
+		for j := range ss[i].Translations {
+			ss[i].Translations[j] = stripGarbage(ss[i].Translations[j])
+		}
+	}
+}
+
+// mergeWords merges duplicate into the Word: keeps the latest progress and joins everything else
+func mergeWords(w, dup *Word) {
+	if dup.LastSeenAt.After(w.LastSeenAt) {
+		w.LastSeenAt = dup.LastSeenAt
+		w.Score = dup.Score
+	}
+	if w.AddedAt.IsZero() || (!dup.AddedAt.IsZero() && dup.AddedAt.Before(w.AddedAt)) {
+		w.AddedAt = dup.AddedAt
+	}
+	if len(w.Senses) == 0 {
+		w.Senses = dup.Senses
+	}
+	if w.Deck == "" {
+		w.Deck = dup.Deck
+	}
+	if w.Meta == "" {
+		w.Meta = dup.Meta
+	}
+	w.AddTags(dup.Tags...)
+
+	join := func(a, b string) string {
+		if a == "" || a == b {
+			return b
+		}
+		if b == "" {
+			return a
+		}
+		return a + "\n" + b
+	}
+	w.Example = join(w.Example, dup.Example)
+	w.Mnemonic = join(w.Mnemonic, dup.Mnemonic)
+	w.Notes = join(w.Notes, dup.Notes)
+}
+
+// dedupKey makes words with the same origin equal. Case matters: Essen and essen are different words.
+func dedupKey(origin string) string {
+	return strings.TrimSpace(origin)
+}
+
+func hasKey[V any](m map[string]V, k string) bool {
+	_, ok := m[k]
+	return ok
+}
+
+// hasGarbage checks if the string contains ANSI escape codes or other control symbols
+func hasGarbage(s string) bool {
+	return re.MatchString(s) || strings.IndexFunc(s, isGarbage) >= 0
+}
+
+// jsonColumns keep values as JSON, where control symbols are escaped
+var jsonColumns = map[string]bool{colSenses: true, colTranslations: true, colConjugation: true, colExamples: true}
+
+// columnHasGarbage checks the column value as hasGarbage, JSON values are checked unescaped
+// as Repair sees them
+func columnHasGarbage(col, v string) bool {
+	if hasGarbage(v) {
+		return true
+	}
+	var data any
+	if !jsonColumns[col] || json.Unmarshal([]byte(v), &data) != nil {
+		return false
+	}
+	return jsonHasGarbage(data)
+}
+
+// jsonHasGarbage checks all strings of decoded JSON value
+func jsonHasGarbage(v any) bool {
+	switch v := v.(type) {
+	case string:
+		return hasGarbage(v)
+	case []any:
+		for _, x := range v {
+			if jsonHasGarbage(x) {
+				return true
+			}
+		}
+	case map[string]any:
+		for _, x := range v {
+			if jsonHasGarbage(x) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// stripGarbage removes ANSI escape codes and other control symbols
+func stripGarbage(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isGarbage(r) {
+			return -1
+		}
+		return r
+	}, StripColors(s))
+}
+
+func isGarbage(r rune) bool {
+	return unicode.IsControl(r) && r != '\n' && r != '\t'
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeStore(t *testing.T, data string) *CSV {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "words.csv")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := OpenCSV(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCheck_LegacyFileIsNotCoerced(t *testing.T) {
+	data := "origin;translation;last_seen_at;score;meta\n" +
+		"Haus;house;bad;x;\n" +
+		"Baum;tree\n" +
+		"gehen;go;2026-09-01T10:00:00Z;2;\n"
+	c := writeStore(t, data)
+
+	ps, err := Check(c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, p := range ps {
+		got = append(got, fmt.Sprintf("%d %s %s: %s", p.Line, p.Kind, p.Origin, p.Msg))
+	}
+	want := []string{
+		`2 malformed Haus: bad last_seen_at "bad"`,
+		`2 malformed Haus: bad score "x"`,
+		`3 malformed Baum: 2 columns instead of 5`,
+		`3 malformed Baum: bad last_seen_at ""`,
+		`3 malformed Baum: bad score ""`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	after, err := os.ReadFile(c.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != data {
+		t.Errorf("checked file is changed:\n%s", after)
+	}
+}
+
+func TestCheck_DuplicatesAreCaseSensitive(t *testing.T) {
+	c := writeStore(t, "")
+	if err := c.saveAll(Words{
+		NewWord("Essen"), NewWord("essen"), NewWord("Weg"), NewWord("weg"), NewWord(" Weg "),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ps, err := Check(c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ps) != 1 || ps[0].Kind != ProblemDuplicate || ps[0].Origin != " Weg " {
+		t.Fatalf("want only \" Weg \" duplicate, got %v", ps)
+	}
+
+	if _, err := Repair(c, nil); err != nil {
+		t.Fatal(err)
+	}
+	ws, err := c.Find(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var origins []string
+	for _, w := range ws {
+		origins = append(origins, w.Origin)
+	}
+	if got := strings.Join(origins, ","); got != "Essen,essen,Weg,weg" {
+		t.Errorf("got words %s after repair", got)
+	}
+}
+
+func TestRepair_StripsGarbageCheckFinds(t *testing.T) {
+	const red = "\x1b[31m"
+	w := NewWord("gehen" + red)
+	w.Deck, w.Notes, w.Tags = "A1\x07", "notes\x00", []string{red + "verbs"}
+	w.Senses = []Sense{{Translations: []string{"go" + red}, Example: "Ich gehe\x1b[0m"}}
+	w.Translations = map[string][]Sense{"ru": {{Translations: []string{"идти\x7f"}}}}
+	w.Examples = []Example{{
+		Text: "Wie geht's?\x1b[1m", Word: "geht\x08", Translations: map[string]string{"en": red + "How are you?"},
+	}}
+	w.Conjugation = Conjugation{{Name: "Präsens\x1b[0m", Forms: []string{"gehe", "gehst\x00"}}}
+
+	c := writeStore(t, "")
+	if err := c.saveAll(Words{w}); err != nil {
+		t.Fatal(err)
+	}
+
+	ps, err := Check(c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range ps {
+		got = append(got, p.Msg)
+	}
+	want := []string{
+		"escape codes or control symbols in origin",
+		"escape codes or control symbols in senses",
+		"escape codes or control symbols in tags",
+		"escape codes or control symbols in deck",
+		"escape codes or control symbols in notes",
+		"escape codes or control symbols in translations",
+		"escape codes or control symbols in conjugation",
+		"escape codes or control symbols in examples",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if _, err := Repair(c, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ps, err := Check(c, nil); err != nil || len(ps) != 0 {
+		t.Errorf("got problems %v, %v after repair", ps, err)
+	}
+
+	fixed, err := c.Get("gehen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fixed.TranslationIn("ru"); got != "идти" {
+		t.Errorf("got ru translation %q", got)
+	}
+	if e := fixed.Examples[0]; e.Text != "Wie geht's?" || e.Word != "geht" || e.Translations["en"] != "How are you?" {
+		t.Errorf("got example %+v", e)
+	}
+	if tn := fixed.Conjugation[0]; tn.Name != "Präsens" || tn.Forms[1] != "gehst" {
+		t.Errorf("got conjugation %+v", tn)
+	}
+}
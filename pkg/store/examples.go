@@ -0,0 +1,96 @@
+package store
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// clozeBlank replaces the word in cloze sentences
+const clozeBlank = "_____"
+
+// Example is an example sentence of the Word found by provider
+type Example struct {
+	// Text is the German sentence
+	Text string `json:"text"`
+	// Word is the Word form used in the sentence, e.g. "ging" for "gehen"
+	Word string `json:"word,omitempty"`
+	// Translations are translations of the sentence, by language code
+	Translations map[string]string `json:"translations,omitempty"`
+}
+
+// TranslationIn returns translation of the sentence in the first of languages it has, or any other one
+func (e Example) TranslationIn(langs ...string) string {
+	for _, l := range langs {
+		if t := e.Translations[l]; t != "" {
+			return t
+		}
+	}
+	// the map order is random, so the same translation is chosen by the smallest language code
+	var lang string
+	for l, t := range e.Translations {
+		if t != "" && (lang == "" || l < lang) {
+			lang = l
+		}
+	}
+	return e.Translations[lang]
+}
+
+// Cloze returns the sentence with the word blanked, false if the word is not found in the sentence.
+// The form is the Word form used in the sentence, or origin if the form is unknown.
+func (e Example) Cloze(origin string) (string, bool) {
+	re := e.clozeRe(origin)
+	if re == nil || !re.MatchString(e.Text) {
+		return "", false
+	}
+	return re.ReplaceAllString(e.Text, "${1}"+clozeBlank+"${3}"), true
+}
+
+// CheckCloze checks if the answer is the word blanked in the sentence
+func (e Example) CheckCloze(answer, origin string) bool {
+	re := e.clozeRe(origin)
+	if re == nil {
+		return false
+	}
+	for _, m := range re.FindAllStringSubmatch(e.Text, -1) {
+		if normalizeAnswer(m[2]) == normalizeAnswer(answer) {
+			return true
+		}
+	}
+	return false
+}
+
+// clozeRe matches the word in the sentence as a whole word, case-insensitive: groups are the char before,
+// the word and the char after. Nouns are matched without the article. \b is not used, it's for ASCII words only.
+func (e Example) clozeRe(origin string) *regexp.Regexp {
+	form := e.Word
+	if form == "" {
+		fs := strings.Fields(origin)
+		if len(fs) == 0 {
+			return nil
+		}
+		form = fs[len(fs)-1]
+	}
+	return regexp.MustCompile(`(?i)(^|[^\pL])(` + regexp.QuoteMeta(form) + `)([^\pL]|$)`)
+}
+
+// encodeExamples serializes examples for persistent store
+func encodeExamples(es []Example) string {
+	if len(es) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(es)
+	return string(b)
+}
+
+// decodeExamples deserializes examples, malformed ones are dropped
+func decodeExamples(s string) []Example {
+	if s == "" {
+		return nil
+	}
+	var es []Example
+	if err := json.Unmarshal([]byte(s), &es); err != nil {
+		return nil
+	}
+	return es
+}
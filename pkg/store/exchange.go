@@ -0,0 +1,120 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Import and export formats
+const (
+	// FormatCSV is the store own format with all the data
+	FormatCSV = "csv"
+	// FormatTSV is `origin<TAB>translation<TAB>tags` lines, e.g. for Anki or spreadsheets
+	FormatTSV = "tsv"
+	// FormatJSON is array of words
+	FormatJSON = "json"
+)
+
+// Export writes words in the format
+func Export(w io.Writer, ws Words, format string) error {
+	switch format {
+	case FormatCSV:
+		rows := make([][]string, len(ws))
+		for i, word := range ws {
+			rows[i] = toRow(*word)
+		}
+		return writeTable(w, rows)
+
+	case FormatTSV:
+		bw := bufio.NewWriter(w)
+		for _, word := range ws {
+			_, err := fmt.Fprintf(bw, "%s\t%s\t%s\n",
+				tsvEscape(word.Origin),
+				tsvEscape(word.Translation()),
+				strings.Join(word.Tags, tagsSep),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return bw.Flush()
+
+	case FormatJSON:
+		if ws == nil {
+			ws = Words{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(ws)
+	}
+
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// Import reads words in the format. CSV files of any previous schema version are supported.
+func Import(r io.Reader, format string) (Words, error) {
+	switch format {
+	case FormatCSV:
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		t, err := readTable(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.migrate(); err != nil {
+			return nil, err
+		}
+		ws := make(Words, len(t.records))
+		for i, rec := range t.records {
+			ws[i] = fromRecord(rec)
+		}
+		return ws, nil
+
+	case FormatTSV:
+		var ws Words
+		sc := bufio.NewScanner(r)
+		for n := 1; sc.Scan(); n++ {
+			line := strings.TrimRight(sc.Text(), "\r")
+			if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			cols := strings.Split(line, "\t")
+			if strings.TrimSpace(cols[0]) == "" {
+				return nil, fmt.Errorf("line %d: empty origin", n)
+			}
+			w := NewWord(strings.TrimSpace(cols[0]))
+			if len(cols) > 1 {
+				w.SetTranslation(cols[1])
+			}
+			if len(cols) > 2 {
+				w.AddTags(ParseTags(cols[2])...)
+			}
+			ws = append(ws, w)
+		}
+		return ws, sc.Err()
+
+	case FormatJSON:
+		var ws Words
+		if err := json.NewDecoder(r).Decode(&ws); err != nil {
+			return nil, err
+		}
+		for _, w := range ws {
+			if w.AddedAt.IsZero() {
+				w.AddedAt = time.Now()
+			}
+		}
+		return ws, nil
+	}
+
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+func tsvEscape(s string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ").Replace(s)
+}
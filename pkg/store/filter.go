@@ -0,0 +1,58 @@
+package store
+
+import "strings"
+
+// Filter is a predicate to choose particular words from the store.
+// nil Filter matches any Word.
+type Filter func(w *Word) bool
+
+// Match checks if Word fits the Filter
+func (f Filter) Match(w *Word) bool {
+	return f == nil || f(w)
+}
+
+// ByDeck matches words from particular deck, ignoring case like tags. Empty deck matches any Word.
+func ByDeck(deck string) Filter {
+	if deck == "" {
+		return nil
+	}
+	return func(w *Word) bool {
+		return sameDeck(w.Deck, deck)
+	}
+}
+
+func sameDeck(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
+
+// ByTags matches words having at least one of the tags. No tags matches any Word.
+func ByTags(tags ...string) Filter {
+	if len(tags) == 0 {
+		return nil
+	}
+	return func(w *Word) bool {
+		for _, t := range tags {
+			if w.HasTag(t) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// NotSuspended matches words which are not suspended from learning
+func NotSuspended(w *Word) bool {
+	return !w.Suspended
+}
+
+// And matches words fitting all the filters
+func And(fs ...Filter) Filter {
+	return func(w *Word) bool {
+		for _, f := range fs {
+			if !f.Match(w) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,324 @@
+package store
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ParseFilter parses a filter expression into a Filter. Expression is a bunch of
+// terms joined by `and`, `or`, `not` and parentheses, e.g.:
+//
+//	score<=2 and tag:verbs and added>2026-09-01 and not suspended
+//
+// Supported terms:
+//
+//	score  (= != < <= > >=) int
+//	added  (= != < <= > >=) date[2006-01-02]
+//	seen   (= != < <= > >=) date[2006-01-02]
+//	tag:<tag>, deck:<deck>
+//	origin:<substring>, translation:<substring>
+//	suspended
+//
+// Values with spaces could be quoted: `deck:"Chapter 1"`. Empty expression matches any Word.
+func ParseFilter(expr string) (Filter, error) {
+	ts, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(ts) == 0 {
+		return nil, nil
+	}
+
+	p := &parser{tokens: ts}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("filter: unexpected %q at %d", p.peek().val, p.peek().pos)
+	}
+
+	return f, nil
+}
+
+const dateLayout = "2006-01-02"
+
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+	pos  int
+}
+
+func isOpChar(r rune) bool {
+	return r == '<' || r == '>' || r == '=' || r == '!' || r == ':'
+}
+
+// lex splits filter expression into tokens
+func lex(s string) ([]token, error) {
+	var ts []token
+	rs := []rune(s)
+
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			ts = append(ts, token{tokLParen, "(", i})
+			i++
+
+		case r == ')':
+			ts = append(ts, token{tokRParen, ")", i})
+			i++
+
+		case isOpChar(r):
+			start := i
+			for i < len(rs) && isOpChar(rs[i]) {
+				i++
+			}
+			op := string(rs[start:i])
+			switch op {
+			case "<", "<=", ">", ">=", "=", "!=", ":":
+			default:
+				return nil, fmt.Errorf("filter: unknown operator %q at %d", op, start)
+			}
+			ts = append(ts, token{tokOp, op, start})
+
+		case r == '"':
+			start := i
+			i++
+			for i < len(rs) && rs[i] != '"' {
+				i++
+			}
+			if i == len(rs) {
+				return nil, fmt.Errorf("filter: unterminated quote at %d", start)
+			}
+			ts = append(ts, token{tokWord, string(rs[start+1 : i]), start})
+			i++
+
+		default:
+			start := i
+			for i < len(rs) && !unicode.IsSpace(rs[i]) && !isOpChar(rs[i]) && rs[i] != '(' && rs[i] != ')' {
+				i++
+			}
+			ts = append(ts, token{tokWord, string(rs[start:i]), start})
+		}
+	}
+
+	return ts, nil
+}
+
+// parser is recursive descent parser of filter expression:
+//
+//	or      = and { "or" and }
+//	and     = not { "and" not }
+//	not     = "not" not | primary
+//	primary = "(" or ")" | term
+//	term    = word [ op word ]
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func (p *parser) done() bool { return p.i >= len(p.tokens) }
+
+func (p *parser) peek() token { return p.tokens[p.i] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	p.i++
+	return t
+}
+
+func (p *parser) isKeyword(kw string) bool {
+	return !p.done() && p.peek().kind == tokWord && strings.EqualFold(p.peek().val, kw)
+}
+
+func (p *parser) parseOr() (Filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(w *Word) bool { return l(w) || right(w) }
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Filter, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(w *Word) bool { return l(w) && right(w) }
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (Filter, error) {
+	if p.isKeyword("not") {
+		p.next()
+		f, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(w *Word) bool { return !f(w) }, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Filter, error) {
+	if p.done() {
+		return nil, fmt.Errorf("filter: unexpected end of expression")
+	}
+
+	t := p.next()
+	switch t.kind {
+	case tokLParen:
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.peek().kind != tokRParen {
+			return nil, fmt.Errorf("filter: missing ')' for '(' at %d", t.pos)
+		}
+		p.next()
+		return f, nil
+
+	case tokWord:
+		return p.parseTerm(t)
+
+	default:
+		return nil, fmt.Errorf("filter: unexpected %q at %d", t.val, t.pos)
+	}
+}
+
+func (p *parser) parseTerm(field token) (Filter, error) {
+	name := strings.ToLower(field.val)
+
+	// bare flags
+	if p.done() || p.peek().kind != tokOp {
+		switch name {
+		case "suspended":
+			return func(w *Word) bool { return w.Suspended }, nil
+		}
+		return nil, fmt.Errorf("filter: unknown term %q at %d", field.val, field.pos)
+	}
+
+	op := p.next()
+	if p.done() || p.peek().kind != tokWord {
+		return nil, fmt.Errorf("filter: missing value after %q at %d", op.val, op.pos)
+	}
+	val := p.next()
+
+	switch name {
+	case "score":
+		n, err := strconv.Atoi(val.val)
+		if err != nil {
+			return nil, fmt.Errorf("filter: bad score %q at %d", val.val, val.pos)
+		}
+		cmp, err := compare(op)
+		if err != nil {
+			return nil, err
+		}
+		return func(w *Word) bool { return cmp(w.Score - n) }, nil
+
+	case "added", "seen":
+		d, err := time.Parse(dateLayout, val.val)
+		if err != nil {
+			return nil, fmt.Errorf("filter: bad date %q at %d, want YYYY-MM-DD", val.val, val.pos)
+		}
+		cmp, err := compare(op)
+		if err != nil {
+			return nil, err
+		}
+		day := d.Format(dateLayout)
+		get := func(w *Word) time.Time { return w.AddedAt }
+		if name == "seen" {
+			get = func(w *Word) time.Time { return w.LastSeenAt }
+		}
+		return func(w *Word) bool {
+			return cmp(strings.Compare(get(w).Format(dateLayout), day))
+		}, nil
+
+	case "tag", "deck", "origin", "translation":
+		if op.val != ":" {
+			return nil, fmt.Errorf("filter: %s supports only ':' at %d", name, op.pos)
+		}
+		v := val.val
+		switch name {
+		case "tag":
+			return func(w *Word) bool { return w.HasTag(v) }, nil
+		case "deck":
+			return func(w *Word) bool { return sameDeck(w.Deck, v) }, nil
+		case "origin":
+			return func(w *Word) bool { return containsFold(w.Origin, v) }, nil
+		default:
+			return func(w *Word) bool { return hasTranslation(w, v) }, nil
+		}
+	}
+
+	return nil, fmt.Errorf("filter: unknown field %q at %d", field.val, field.pos)
+}
+
+// compare makes a check of comparison result (negative, zero, positive) for the operator
+func compare(op token) (func(c int) bool, error) {
+	switch op.val {
+	case "=":
+		return func(c int) bool { return c == 0 }, nil
+	case "!=":
+		return func(c int) bool { return c != 0 }, nil
+	case "<":
+		return func(c int) bool { return c < 0 }, nil
+	case "<=":
+		return func(c int) bool { return c <= 0 }, nil
+	case ">":
+		return func(c int) bool { return c > 0 }, nil
+	case ">=":
+		return func(c int) bool { return c >= 0 }, nil
+	}
+	return nil, fmt.Errorf("filter: operator %q is not supported here at %d", op.val, op.pos)
+}
+
+// hasTranslation checks if substr is a part of translation in any language of the Word
+func hasTranslation(w *Word, substr string) bool {
+	if containsFold(w.Translation(), substr) {
+		return true
+	}
+	for l := range w.Translations {
+		if containsFold(w.TranslationIn(l), substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,163 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseFilter_Match(t *testing.T) {
+	gehen := &Word{
+		Origin:     "gehen",
+		Senses:     ParseSenses("go, walk; work"),
+		Score:      2,
+		Tags:       []string{"verbs", "a1"},
+		Deck:       "Chapter 1",
+		AddedAt:    date("2026-09-10"),
+		LastSeenAt: date("2026-09-12"),
+		Translations: map[string][]Sense{
+			"en": ParseSenses("go, walk; work"),
+			"ru": ParseSenses("идти"),
+		},
+	}
+	haus := &Word{
+		Origin:     "das Haus",
+		Senses:     ParseSenses("house"),
+		Score:      4,
+		Tags:       []string{"nouns"},
+		Deck:       "Chapter 2",
+		AddedAt:    date("2026-08-01"),
+		LastSeenAt: date("2026-09-20"),
+		Suspended:  true,
+	}
+
+	tbl := []struct {
+		expr        string
+		gehen, haus bool
+	}{
+		{"", true, true},
+		{"score<=2 and tag:verbs and added>2026-09-01 and not suspended", true, false},
+
+		// fields
+		{"score=2", true, false},
+		{"score!=2", false, true},
+		{"score<3", true, false},
+		{"score>3", false, true},
+		{"score>=4", false, true},
+		{"added>2026-09-01", true, false},
+		{"added<2026-09-01", false, true},
+		{"added=2026-08-01", false, true},
+		{"added<=2026-09-10", true, true},
+		{"seen<2026-01-01", false, false},
+		{"seen>2026-09-15", false, true},
+		{"tag:verbs", true, false},
+		{"tag:NOUNS", false, true},
+		{`deck:"Chapter 1"`, true, false},
+		{"deck:chapter", false, false},
+		{`deck:"chapter 2"`, false, true},
+		{"origin:geh", true, false},
+		{"origin:HAUS", false, true},
+		{"translation:walk", true, false},
+		{"translation:hou", false, true},
+		{"translation:ИДТ", true, false},
+		{"suspended", false, true},
+
+		// precedence: not > and > or
+		{"tag:verbs or tag:nouns and score<3", true, false},
+		{"(tag:verbs or tag:nouns) and score<3", true, false},
+		{"(tag:verbs or tag:nouns) and score>3", false, true},
+		{"tag:verbs or tag:nouns and score>3", true, true},
+		{"not tag:verbs and score>3", false, true},
+		{"not (tag:verbs or suspended)", false, false},
+		{"not not suspended", false, true},
+		{"NOT suspended AND tag:verbs", true, false},
+		{"((score=2))", true, false},
+
+		// quoted values
+		{`deck:"Chapter 2" or origin:"das Haus"`, false, true},
+		{`origin:"s h"`, false, true},
+		{`translation:"go, walk"`, true, false},
+	}
+
+	for _, tt := range tbl {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := ParseFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := f.Match(gehen); got != tt.gehen {
+				t.Errorf("gehen: got %v, want %v", got, tt.gehen)
+			}
+			if got := f.Match(haus); got != tt.haus {
+				t.Errorf("das Haus: got %v, want %v", got, tt.haus)
+			}
+		})
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	tbl := []struct {
+		expr, err string
+	}{
+		{"score<<2", `unknown operator "<<" at 5`},
+		{"score=x", `bad score "x" at 6`},
+		{"added>2026-13-01", `bad date "2026-13-01" at 6`},
+		{"tag=verbs", "tag supports only ':' at 3"},
+		{"score:2", `operator ":" is not supported here at 5`},
+		{"color:red", `unknown field "color" at 0`},
+		{"suspended and frozen", `unknown term "frozen" at 14`},
+		{"score<", `missing value after "<" at 5`},
+		{"(score<2", "missing ')' for '(' at 0"},
+		{"score<2)", `unexpected ")" at 7`},
+		{"score<2 and", "unexpected end of expression"},
+		{"not", "unexpected end of expression"},
+		{`deck:"Chapter 1`, "unterminated quote at 5"},
+		{"and suspended", `unknown term "and" at 0`},
+		{"<2", `unexpected "<" at 0`},
+	}
+
+	for _, tt := range tbl {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseFilter(tt.expr)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got %q, want it to contain %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestFilter_MatchNil(t *testing.T) {
+	var f Filter
+	if !f.Match(&Word{}) {
+		t.Error("nil filter should match any word")
+	}
+	if !And(nil, ByDeck(""), ByTags()).Match(&Word{}) {
+		t.Error("empty filters should match any word")
+	}
+}
+
+func TestByDeck(t *testing.T) {
+	w := &Word{Deck: "Chapter 1"}
+	// the same rule as deck: of filter expression
+	f, err := ParseFilter(`deck:"chapter 1"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ByDeck("chapter 1").Match(w) || !f.Match(w) {
+		t.Error("deck should match ignoring case")
+	}
+	if ByDeck("Chapter").Match(w) {
+		t.Error("deck should match as a whole")
+	}
+}
@@ -0,0 +1,205 @@
+package store
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// reviews schema
+var reviewTitles = []string{"at", "origin", "remembered", "score"}
+
+const (
+	reviewAt int = iota
+	reviewOrigin
+	reviewRemembered
+	reviewScore
+)
+
+// Review is a single answer on the Word during learning
+type Review struct {
+	At         time.Time
+	Origin     string
+	Remembered bool
+	// Score is Word score before the answer
+	Score int
+}
+
+// ReviewLog is append-only .csv log of all reviews
+type ReviewLog struct {
+	Path string
+}
+
+// NewReviewLog creates a new review log. Creates a new CSV file, if it does not exist.
+func NewReviewLog(path string) (*ReviewLog, error) {
+	if !isFileExist(path) {
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return nil, fmt.Errorf("can't make review log dir: %w", err)
+		}
+		if err := (ReviewLog{Path: path}).saveAll(nil); err != nil {
+			return nil, fmt.Errorf("can't make review log: %w", err)
+		}
+	}
+	return &ReviewLog{Path: path}, nil
+}
+
+// Add appends the Review to the log
+func (l ReviewLog) Add(r Review) error {
+	f, err := os.OpenFile(filepath.Clean(l.Path), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	w := csv.NewWriter(f)
+	w.Comma = ';'
+	if err := w.Write(r.toRow()); err != nil {
+		return err
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// RemoveLast removes the latest review from the log, e.g. to undo the answer.
+// Other rows are kept as is, even malformed ones.
+func (l ReviewLog) RemoveLast() error {
+	rows, err := l.readRows()
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	fields := make([][]string, len(rows)-1)
+	for i := range fields {
+		fields[i] = rows[i].fields
+	}
+	return l.writeRows(fields)
+}
+
+// All returns all reviews in chronological order, malformed rows are skipped
+func (l ReviewLog) All() ([]Review, error) {
+	rows, err := l.readRows()
+	if err != nil {
+		return nil, err
+	}
+
+	rs := make([]Review, 0, len(rows))
+	for _, row := range rows {
+		r, err := reviewFromRow(row.fields)
+		if err != nil {
+			continue
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+type reviewRow struct {
+	line   int
+	fields []string
+}
+
+// readRows reads raw log rows without header
+func (l ReviewLog) readRows() ([]reviewRow, error) {
+	f, err := os.Open(filepath.Clean(l.Path))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	r := csv.NewReader(f)
+	r.Comma = ';'
+	r.FieldsPerRecord = -1
+
+	if _, err := r.Read(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var rows []reviewRow
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := r.FieldPos(0)
+		rows = append(rows, reviewRow{line: line, fields: row})
+	}
+	return rows, nil
+}
+
+// saveAll overrides the log with reviews
+func (l ReviewLog) saveAll(rs []Review) error {
+	rows := make([][]string, len(rs))
+	for i, r := range rs {
+		rows[i] = r.toRow()
+	}
+	return l.writeRows(rows)
+}
+
+// writeRows overrides the log with raw rows
+func (l ReviewLog) writeRows(rows [][]string) error {
+	f, err := os.OpenFile(filepath.Clean(l.Path), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	w := csv.NewWriter(f)
+	w.Comma = ';'
+	if err := w.Write(reviewTitles); err != nil {
+		return err
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func (r Review) toRow() []string {
+	return []string{
+		r.At.Format(time.RFC3339),
+		r.Origin,
+		strconv.FormatBool(r.Remembered),
+		strconv.Itoa(r.Score),
+	}
+}
+
+func reviewFromRow(row []string) (Review, error) {
+	if len(row) != len(reviewTitles) {
+		return Review{}, fmt.Errorf("want %d columns, got %d", len(reviewTitles), len(row))
+	}
+
+	at, err := time.Parse(time.RFC3339, row[reviewAt])
+	if err != nil {
+		return Review{}, fmt.Errorf("bad time %q", row[reviewAt])
+	}
+	remembered, err := strconv.ParseBool(row[reviewRemembered])
+	if err != nil {
+		return Review{}, fmt.Errorf("bad remembered flag %q", row[reviewRemembered])
+	}
+	score, err := strconv.Atoi(row[reviewScore])
+	if err != nil {
+		return Review{}, fmt.Errorf("bad score %q", row[reviewScore])
+	}
+
+	return Review{
+		At:         at,
+		Origin:     row[reviewOrigin],
+		Remembered: remembered,
+		Score:      score,
+	}, nil
+}
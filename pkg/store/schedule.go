@@ -0,0 +1,35 @@
+package store
+
+import "time"
+
+const day = 24 * time.Hour
+
+// Intervals are how long to wait before the next review of a Word, by its score
+var Intervals = []time.Duration{
+	0,
+	1 * day,
+	3 * day,
+	7 * day,
+	14 * day,
+	30 * day,
+}
+
+// DueAt returns time of the next review of the Word. Never seen words are due at zero time.
+func (w *Word) DueAt() time.Time {
+	if w.LastSeenAt.IsZero() {
+		return time.Time{}
+	}
+	score := w.Score
+	if score < MinScore {
+		score = MinScore
+	}
+	if score >= len(Intervals) {
+		score = len(Intervals) - 1
+	}
+	return w.LastSeenAt.Add(Intervals[score])
+}
+
+// IsDue checks if the Word should be reviewed at the time
+func (w *Word) IsDue(at time.Time) bool {
+	return !w.DueAt().After(at)
+}
@@ -0,0 +1,220 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// schema columns
+const (
+	colOrigin       = "origin"
+	colSenses       = "senses"
+	colLastSeenAt   = "last_seen_at"
+	colScore        = "score"
+	colMeta         = "meta"
+	colTags         = "tags"
+	colDeck         = "deck"
+	colAddedAt      = "added_at"
+	colSuspended    = "suspended"
+	colExample      = "example"
+	colMnemonic     = "mnemonic"
+	colNotes        = "notes"
+	colTranslations = "translations"
+	colConjugation  = "conjugation"
+	colExamples     = "examples"
+
+	// colTranslation is plain translation column of schema v1-v3, replaced by colSenses
+	colTranslation = "translation"
+)
+
+// titles are columns of the current schema in the file order
+var titles = []string{
+	colOrigin, colSenses, colLastSeenAt, colScore, colMeta, colTags, colDeck, colAddedAt, colSuspended,
+	colExample, colMnemonic, colNotes, colTranslations, colConjugation, colExamples,
+}
+
+// versionPrefix starts the first line of a file which keeps schema version.
+// Files without it are made before versioning (v1-v5), their version is guessed by header.
+const versionPrefix = "#karten:schema="
+
+// ErrNewerSchema means file is made by newer version of the app
+var ErrNewerSchema = errors.New("file schema is newer than supported, please update karten")
+
+// record is one row of a file, mapped by column names
+type record map[string]string
+
+// migration upgrades a record from the schema version `from` to `from+1`
+type migration struct {
+	from int
+	desc string
+	up   func(r record)
+}
+
+// migrations are all registered schema upgrades in order. To change the schema, add a new step here
+// and update titles, the current version is always the last step version + 1.
+var migrations = []migration{
+	{from: 1, desc: "add tags and deck", up: func(r record) {}},
+	{from: 2, desc: "add added_at and suspended", up: func(r record) {
+		r[colSuspended] = strconv.FormatBool(false)
+	}},
+	{from: 3, desc: "replace plain translation with senses", up: func(r record) {
+		r[colSenses] = encodeSenses(ParseSenses(r[colTranslation]))
+		delete(r, colTranslation)
+	}},
+	{from: 4, desc: "add example, mnemonic and notes", up: func(r record) {}},
+	{from: 5, desc: "add translations by language", up: func(r record) {}},
+	{from: 6, desc: "add verb conjugation", up: func(r record) {}},
+	{from: 7, desc: "add examples from provider", up: func(r record) {}},
+}
+
+// SchemaVersion is the current version of the store schema
+var SchemaVersion = migrations[len(migrations)-1].from + 1
+
+// legacyHeaders are headers of files made before schema versioning
+var legacyHeaders = map[int][]string{
+	1: {colOrigin, colTranslation, colLastSeenAt, colScore, colMeta},
+	2: {colOrigin, colTranslation, colLastSeenAt, colScore, colMeta, colTags, colDeck},
+	3: {colOrigin, colTranslation, colLastSeenAt, colScore, colMeta, colTags, colDeck, colAddedAt, colSuspended},
+	4: {colOrigin, colSenses, colLastSeenAt, colScore, colMeta, colTags, colDeck, colAddedAt, colSuspended},
+	5: {colOrigin, colSenses, colLastSeenAt, colScore, colMeta, colTags, colDeck, colAddedAt, colSuspended,
+		colExample, colMnemonic, colNotes},
+}
+
+// table is parsed file content
+type table struct {
+	version int
+	header  []string
+	records []record
+	// lines are file line numbers of records, to report problems
+	lines []int
+	// widths are numbers of fields in the records rows
+	widths []int
+}
+
+// readTable parses file content and maps columns by header names
+func readTable(data []byte) (*table, error) {
+	t := &table{}
+
+	lineOffset := 0
+	if bytes.HasPrefix(data, []byte(versionPrefix)) {
+		line, rest, _ := bytes.Cut(data, []byte("\n"))
+		v, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(string(line), versionPrefix)))
+		if err != nil {
+			return nil, fmt.Errorf("bad schema version line %q", line)
+		}
+		t.version = v
+		data = rest
+		lineOffset = 1
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = ';'
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("no header in file")
+		}
+		return nil, err
+	}
+	t.header = header
+
+	if t.version == 0 {
+		t.version, err = guessVersion(header)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rec := record{}
+		for i, col := range header {
+			if i < len(row) {
+				rec[col] = row[i]
+			}
+		}
+		line, _ := r.FieldPos(0)
+		t.records = append(t.records, rec)
+		t.lines = append(t.lines, line+lineOffset)
+		t.widths = append(t.widths, len(row))
+	}
+
+	return t, nil
+}
+
+// guessVersion finds schema version of a file made before versioning by its header
+func guessVersion(header []string) (int, error) {
+	for v := len(legacyHeaders); v > 0; v-- {
+		if hasColumns(header, legacyHeaders[v]) {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown file schema: %s", strings.Join(header, ";"))
+}
+
+func hasColumns(header, cols []string) bool {
+	has := map[string]bool{}
+	for _, h := range header {
+		has[h] = true
+	}
+	for _, c := range cols {
+		if !has[c] {
+			return false
+		}
+	}
+	return true
+}
+
+// migrate upgrades table records up to the current schema version
+func (t *table) migrate() error {
+	if t.version > SchemaVersion {
+		return fmt.Errorf("schema v%d, supported v%d: %w", t.version, SchemaVersion, ErrNewerSchema)
+	}
+
+	for _, m := range migrations {
+		if m.from < t.version {
+			continue
+		}
+		for _, r := range t.records {
+			m.up(r)
+		}
+		t.version = m.from + 1
+	}
+	t.header = titles
+
+	return nil
+}
+
+// writeTable writes version line, header and rows of the current schema
+func writeTable(w io.Writer, rows [][]string) error {
+	bw := bufio.NewWriter(w)
+	if _, err := fmt.Fprintf(bw, "%s%d\n", versionPrefix, SchemaVersion); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(bw)
+	cw.Comma = ';'
+	if err := cw.Write(titles); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixtureWords are words of testdata/schema files, with fields of the schema version only
+func fixtureWords(v int) Words {
+	gehen := &Word{
+		Origin:     "gehen",
+		Senses:     ParseSenses("go, walk; work"),
+		LastSeenAt: time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC),
+		Score:      2,
+		Meta:       "gehen\ngeht · ging · ist gegangen",
+	}
+	haus := &Word{
+		Origin: "das Haus",
+		Senses: ParseSenses("house"),
+	}
+
+	if v >= 2 {
+		gehen.Tags, gehen.Deck = []string{"verbs", "a1"}, "Chapter 1"
+	}
+	if v >= 3 {
+		gehen.AddedAt = time.Date(2026, 8, 20, 8, 0, 0, 0, time.UTC)
+		haus.AddedAt, haus.Suspended = time.Date(2026, 8, 21, 8, 0, 0, 0, time.UTC), true
+	}
+	if v >= 5 {
+		gehen.Example, gehen.Mnemonic, gehen.Notes = "Ich gehe nach Hause.", "go-go", "irregular"
+	}
+	if v >= 6 {
+		gehen.Translations = map[string][]Sense{"ru": ParseSenses("идти")}
+	}
+	if v >= 7 {
+		gehen.Conjugation = Conjugation{{Name: "Präsens", Forms: []string{"gehe", "gehst", "geht", "gehen", "geht", "gehen"}}}
+	}
+	return Words{gehen, haus}
+}
+
+func TestCSV_MigrateAllVersions(t *testing.T) {
+	for v := 1; v < SchemaVersion; v++ {
+		t.Run(fmt.Sprintf("v%d", v), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "words.csv")
+			if err := copyFile(filepath.Join("testdata", "schema", fmt.Sprintf("v%d.csv", v)), path); err != nil {
+				t.Fatal(err)
+			}
+
+			s, err := NewCSV(path)
+			if err != nil {
+				t.Fatalf("can't open: %v", err)
+			}
+			got, err := s.Find(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := fixtureWords(v)
+			if len(got) != len(want) {
+				t.Fatalf("got %d words, want %d", len(got), len(want))
+			}
+			for i := range want {
+				if !reflect.DeepEqual(got[i], want[i]) {
+					t.Errorf("word %d:\n got %+v\nwant %+v", i, got[i], want[i])
+				}
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if head := fmt.Sprintf("%s%d\n%s\n", versionPrefix, SchemaVersion, strings.Join(titles, ";")); !strings.HasPrefix(string(data), head) {
+				t.Errorf("file is not rewritten in the current schema:\n%s", data)
+			}
+			if _, err := os.Stat(fmt.Sprintf("%s.v%d.bak", path, v)); err != nil {
+				t.Errorf("no backup: %v", err)
+			}
+		})
+	}
+}
+
+func TestCSV_NewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.csv")
+	data := fmt.Sprintf("%s%d\n%s\n", versionPrefix, SchemaVersion+1, strings.Join(titles, ";"))
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewCSV(path); err == nil || !strings.Contains(err.Error(), ErrNewerSchema.Error()) {
+		t.Errorf("got %v, want %v", err, ErrNewerSchema)
+	}
+}
+
+func TestReadTable_Lines(t *testing.T) {
+	tbl := []struct {
+		file  string
+		lines []int
+	}{
+		// multiline meta of the first word takes two lines
+		{"v4.csv", []int{2, 4}},
+		// version line is counted too
+		{"v5.csv", []int{3, 5}},
+	}
+
+	for _, tt := range tbl {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", "schema", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tb, err := readTable(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tb.lines, tt.lines) {
+				t.Errorf("got lines %v, want %v", tb.lines, tt.lines)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+const (
+	sensesSep       = ";"
+	translationsSep = ","
+)
+
+// Sense is one particular meaning of the Word with its own translations
+type Sense struct {
+	Translations []string `json:"translations"`
+	Example      string   `json:"example,omitempty"`
+}
+
+func (s Sense) String() string {
+	return strings.Join(s.Translations, translationsSep+" ")
+}
+
+// ParseSenses parses user input like "go, walk; work, function" into senses:
+// senses are separated by semicolon, translations of a sense by comma.
+func ParseSenses(s string) []Sense {
+	var ss []Sense
+	for _, raw := range strings.Split(s, sensesSep) {
+		var ts []string
+		for _, t := range strings.Split(raw, translationsSep) {
+			if t = strings.TrimSpace(t); t != "" {
+				ts = append(ts, t)
+			}
+		}
+		if len(ts) > 0 {
+			ss = append(ss, Sense{Translations: ts})
+		}
+	}
+	return ss
+}
+
+// FormatSenses makes a plain string from senses, it is reversible by ParseSenses
+// unless senses have examples.
+func FormatSenses(ss []Sense) string {
+	parts := make([]string, len(ss))
+	for i, s := range ss {
+		parts[i] = s.String()
+	}
+	return strings.Join(parts, sensesSep+" ")
+}
+
+// encodeSenses serializes senses for persistent store
+func encodeSenses(ss []Sense) string {
+	if len(ss) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(ss)
+	return string(b)
+}
+
+// decodeSenses deserializes senses, plain translation strings from previous store
+// versions are parsed as well.
+func decodeSenses(s string) []Sense {
+	if strings.HasPrefix(s, "[") {
+		var ss []Sense
+		if err := json.Unmarshal([]byte(s), &ss); err == nil {
+			return ss
+		}
+	}
+	return ParseSenses(s)
+}
+
+// encodeTranslations serializes senses by language for persistent store
+func encodeTranslations(ts map[string][]Sense) string {
+	if len(ts) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(ts)
+	return string(b)
+}
+
+// decodeTranslations deserializes senses by language, malformed ones are dropped
+func decodeTranslations(s string) map[string][]Sense {
+	if s == "" {
+		return nil
+	}
+	var ts map[string][]Sense
+	if err := json.Unmarshal([]byte(s), &ts); err != nil {
+		return nil
+	}
+	return ts
+}
+
+// normalizeAnswer makes answers comparable: lowercase without extra spaces and punctuation
+func normalizeAnswer(s string) string {
+	s = strings.TrimFunc(strings.ToLower(s), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,180 @@
+package store
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// sessions schema
+var sessionTitles = []string{"start", "end", "reviewed", "remembered", "forgotten", "promoted", "demoted"}
+
+const (
+	sessionStart int = iota
+	sessionEnd
+	sessionReviewed
+	sessionRemembered
+	sessionForgotten
+	sessionPromoted
+	sessionDemoted
+)
+
+// Session is a summary of a single learning session
+type Session struct {
+	Start, End time.Time
+	// Reviewed is number of answers, Remembered + Forgotten
+	Reviewed, Remembered, Forgotten int
+	// Promoted and Demoted are numbers of words which score was increased or decreased
+	Promoted, Demoted int
+}
+
+// Duration returns time spent in the Session
+func (s Session) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Accuracy returns percentage of remembered words
+func (s Session) Accuracy() int {
+	if s.Reviewed == 0 {
+		return 0
+	}
+	return s.Remembered * 100 / s.Reviewed
+}
+
+// SessionLog is append-only .csv log of all learning sessions
+type SessionLog struct {
+	Path string
+}
+
+// NewSessionLog creates a new session log. Creates a new CSV file, if it does not exist.
+func NewSessionLog(path string) (*SessionLog, error) {
+	if !isFileExist(path) {
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return nil, fmt.Errorf("can't make session log dir: %w", err)
+		}
+		if err := (SessionLog{Path: path}).create(); err != nil {
+			return nil, fmt.Errorf("can't make session log: %w", err)
+		}
+	}
+	return &SessionLog{Path: path}, nil
+}
+
+// Add appends the Session to the log
+func (l SessionLog) Add(s Session) error {
+	f, err := os.OpenFile(filepath.Clean(l.Path), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	w := csv.NewWriter(f)
+	w.Comma = ';'
+	if err := w.Write(s.toRow()); err != nil {
+		return err
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// All returns all sessions in chronological order, malformed rows are skipped
+func (l SessionLog) All() ([]Session, error) {
+	f, err := os.Open(filepath.Clean(l.Path))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	r := csv.NewReader(f)
+	r.Comma = ';'
+	r.FieldsPerRecord = -1
+
+	if _, err := r.Read(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ss []Session
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		s, err := sessionFromRow(row)
+		if err != nil {
+			continue
+		}
+		ss = append(ss, s)
+	}
+	return ss, nil
+}
+
+func (l SessionLog) create() error {
+	f, err := os.OpenFile(filepath.Clean(l.Path), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	w := csv.NewWriter(f)
+	w.Comma = ';'
+	if err := w.Write(sessionTitles); err != nil {
+		return err
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func (s Session) toRow() []string {
+	return []string{
+		s.Start.Format(time.RFC3339),
+		s.End.Format(time.RFC3339),
+		strconv.Itoa(s.Reviewed),
+		strconv.Itoa(s.Remembered),
+		strconv.Itoa(s.Forgotten),
+		strconv.Itoa(s.Promoted),
+		strconv.Itoa(s.Demoted),
+	}
+}
+
+func sessionFromRow(row []string) (Session, error) {
+	if len(row) != len(sessionTitles) {
+		return Session{}, fmt.Errorf("want %d columns, got %d", len(sessionTitles), len(row))
+	}
+
+	var s Session
+	var err error
+	if s.Start, err = time.Parse(time.RFC3339, row[sessionStart]); err != nil {
+		return Session{}, fmt.Errorf("bad start %q", row[sessionStart])
+	}
+	if s.End, err = time.Parse(time.RFC3339, row[sessionEnd]); err != nil {
+		return Session{}, fmt.Errorf("bad end %q", row[sessionEnd])
+	}
+
+	counts := []struct {
+		col int
+		val *int
+	}{
+		{sessionReviewed, &s.Reviewed},
+		{sessionRemembered, &s.Remembered},
+		{sessionForgotten, &s.Forgotten},
+		{sessionPromoted, &s.Promoted},
+		{sessionDemoted, &s.Demoted},
+	}
+	for _, c := range counts {
+		if *c.val, err = strconv.Atoi(row[c.col]); err != nil {
+			return Session{}, fmt.Errorf("bad %s %q", sessionTitles[c.col], row[c.col])
+		}
+	}
+
+	return s, nil
+}
@@ -9,8 +9,9 @@ import (
 )
 
 const (
-	minScore = 0
-	maxScore = 5
+	// MinScore and MaxScore are bounds of the Word score
+	MinScore = 0
+	MaxScore = 5
 
 	ansi = "[\u001B\u009B][[\\]()#;?]*(?:(?:(?:[a-zA-Z\\d]*(?:;[a-zA-Z\\d]*)*)?\u0007)|(?:(?:\\d{1,4}(?:;\\d{0,4})*)?[\\dA-PRZcf-ntqry=><~]))"
 )
@@ -19,24 +20,117 @@ var re = regexp.MustCompile(ansi)
 
 // Word is word for learning with all required metadata.
 type Word struct {
-	Origin, Translation, Meta string
-	LastSeenAt                time.Time
-	Score                     int
+	Origin     string    `json:"origin"`
+	Meta       string    `json:"meta,omitempty"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	AddedAt    time.Time `json:"added_at"`
+	Score      int       `json:"score"`
+
+	// Senses are different meanings of the Word, each with own translations
+	Senses []Sense `json:"senses"`
+	// Translations are senses in other languages found by provider, by language code ("en", "ru", etc.)
+	Translations map[string][]Sense `json:"translations,omitempty"`
+	// Conjugation are conjugation tables of a verb found by provider
+	Conjugation Conjugation `json:"conjugation,omitempty"`
+	// Examples are example sentences found by provider, unlike user's own Example
+	Examples []Example `json:"examples,omitempty"`
+
+	// Suspended words are excluded from learning
+	Suspended bool `json:"suspended"`
+
+	// Deck is a named collection (textbook chapter, topic, etc.) the Word belongs to
+	Deck string `json:"deck,omitempty"`
+	// Tags are free-form labels to group words across decks
+	Tags []string `json:"tags,omitempty"`
+
+	// Example is user's own example sentence
+	Example string `json:"example,omitempty"`
+	// Mnemonic is a hint to remember the Word
+	Mnemonic string `json:"mnemonic,omitempty"`
+	// Notes are any free-form user's notes
+	Notes string `json:"notes,omitempty"`
 }
 
 // NewWord create a new Word instance, including try to get word metadata form
 // VerbFormen.
 func NewWord(raw string) *Word {
-	return &Word{Origin: raw}
+	return &Word{Origin: raw, AddedAt: time.Now()}
 }
 
 func (w Word) String() string {
 	return w.Origin
 }
 
+// Translation returns all translations of the Word as a plain string
+func (w Word) Translation() string {
+	return FormatSenses(w.Senses)
+}
+
+// SetTranslation replaces Word senses by parsed user input, like "go, walk; work, function".
+// Senses are translations in one of languages too, so the same translations are replaced with them.
+func (w *Word) SetTranslation(s string) {
+	old := FormatSenses(w.Senses)
+	w.Senses = ParseSenses(s)
+	for l, ss := range w.Translations {
+		if FormatSenses(ss) == old {
+			w.Translations[l] = w.Senses
+		}
+	}
+}
+
+// SensesIn returns senses in the first of languages the Word has translations in, or Senses
+func (w Word) SensesIn(langs ...string) []Sense {
+	for _, l := range langs {
+		if ss := w.Translations[l]; len(ss) > 0 {
+			return ss
+		}
+	}
+	return w.Senses
+}
+
+// TranslationIn returns translations in the first of languages the Word has, as a plain string
+func (w Word) TranslationIn(langs ...string) string {
+	return FormatSenses(w.SensesIn(langs...))
+}
+
+// SetTranslationIn replaces senses in the first of languages the Word has translations in, or Senses.
+// Senses are replaced too if they are translations in the language.
+func (w *Word) SetTranslationIn(s string, langs ...string) {
+	for _, l := range langs {
+		if old := w.Translations[l]; len(old) > 0 {
+			w.Translations[l] = ParseSenses(s)
+			if FormatSenses(old) == FormatSenses(w.Senses) {
+				w.Senses = w.Translations[l]
+			}
+			return
+		}
+	}
+	w.SetTranslation(s)
+}
+
+// CheckAnswer checks if answer is one of translations of any Word sense, in any language
+func (w *Word) CheckAnswer(answer string) bool {
+	a := normalizeAnswer(answer)
+	if a == "" {
+		return false
+	}
+	senses := w.Senses
+	for _, ss := range w.Translations {
+		senses = append(senses[:len(senses):len(senses)], ss...)
+	}
+	for _, s := range senses {
+		for _, t := range s.Translations {
+			if normalizeAnswer(t) == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // IncScore increases particular Word score
 func (w *Word) IncScore() {
-	if w.Score < maxScore {
+	if w.Score < MaxScore {
 		w.Score++
 	}
 	w.LastSeenAt = time.Now()
@@ -44,11 +138,140 @@ func (w *Word) IncScore() {
 
 // DecScore decrease particular Word score
 func (w *Word) DecScore() {
-	if w.Score > minScore {
+	if w.Score > MinScore {
 		w.Score--
 	}
 }
 
+// RemoveTags removes tags from the Word
+func (w *Word) RemoveTags(tags ...string) {
+	var kept []string
+	for _, t := range w.Tags {
+		removed := false
+		for _, r := range tags {
+			if strings.EqualFold(t, r) {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			kept = append(kept, t)
+		}
+	}
+	w.Tags = kept
+}
+
+// ResetProgress forgets all learning progress of the Word
+func (w *Word) ResetProgress() {
+	w.Score = MinScore
+	w.LastSeenAt = time.Time{}
+}
+
+// HasTag indicates if Word is tagged by particular tag
+func (w *Word) HasTag(tag string) bool {
+	for _, t := range w.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTags adds new tags to the Word, skipping the ones Word already has
+func (w *Word) AddTags(tags ...string) {
+	for _, t := range tags {
+		if t != "" && !w.HasTag(t) {
+			w.Tags = append(w.Tags, t)
+		}
+	}
+}
+
+// AddMeanings merges the other Word of the same origin into the Word, e.g. another meaning of "die Bank":
+// senses, translations and examples it doesn't have yet, new lines of meta, tags and user's notes.
+// Progress of the Word is kept.
+func (w *Word) AddMeanings(other *Word) {
+	w.Senses = appendNewSenses(w.Senses, other.Senses)
+	for l, ss := range other.Translations {
+		if w.Translations == nil {
+			w.Translations = map[string][]Sense{}
+		}
+		w.Translations[l] = appendNewSenses(w.Translations[l], ss)
+	}
+	for _, e := range other.Examples {
+		if !hasExample(w.Examples, e.Text) {
+			w.Examples = append(w.Examples, e)
+		}
+	}
+	if len(w.Conjugation) == 0 {
+		w.Conjugation = other.Conjugation
+	}
+	if w.Deck == "" {
+		w.Deck = other.Deck
+	}
+	w.AddTags(other.Tags...)
+
+	// meta of a meaning repeats the origin, only its forms are new
+	w.Meta = strings.TrimSpace(joinNewLines(w.Meta, other.Meta))
+	w.Example = joinNewLines(w.Example, other.Example)
+	w.Mnemonic = joinNewLines(w.Mnemonic, other.Mnemonic)
+	w.Notes = joinNewLines(w.Notes, other.Notes)
+}
+
+// appendNewSenses appends senses missed in ss to a copy of ss, senses could share arrays with translations
+func appendNewSenses(ss, more []Sense) []Sense {
+	res := append([]Sense{}, ss...)
+	for _, m := range more {
+		known := false
+		for _, s := range res {
+			if s.String() == m.String() {
+				known = true
+				break
+			}
+		}
+		if !known {
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+func hasExample(es []Example, text string) bool {
+	for _, e := range es {
+		if e.Text == text {
+			return true
+		}
+	}
+	return false
+}
+
+// joinNewLines appends lines of b missed in a
+func joinNewLines(a, b string) string {
+	if a == "" || b == "" {
+		return a + b
+	}
+	lines := strings.Split(a, "\n")
+	for _, l := range strings.Split(b, "\n") {
+		known := false
+		for _, x := range lines {
+			if x == l {
+				known = true
+				break
+			}
+		}
+		if !known {
+			lines = append(lines, l)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseTags splits raw user input like "verbs, chapter1 work" into separate tags
+func ParseTags(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
 // HasMeta indicate if Word has Meta data
 func (w *Word) HasMeta() bool {
 	return w.Meta != ""
@@ -0,0 +1,44 @@
+package store
+
+import "testing"
+
+func TestWord_SetTranslationIn(t *testing.T) {
+	newWord := func() *Word {
+		return &Word{
+			Origin: "gehen",
+			Senses: ParseSenses("идти"),
+			Translations: map[string][]Sense{
+				"ru": ParseSenses("идти"),
+				"en": ParseSenses("go, walk"),
+			},
+		}
+	}
+
+	tbl := []struct {
+		name           string
+		langs          []string
+		senses, ru, en string
+	}{
+		{"language of senses", []string{"ru"}, "ходить", "ходить", "go, walk"},
+		{"other language", []string{"en"}, "идти", "идти", "ходить"},
+		{"the first language the word has", []string{"es", "en"}, "идти", "идти", "ходить"},
+		{"no languages", nil, "ходить", "ходить", "go, walk"},
+		{"unknown language", []string{"es"}, "ходить", "ходить", "go, walk"},
+	}
+
+	for _, tt := range tbl {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newWord()
+			w.SetTranslationIn("ходить", tt.langs...)
+			if got := w.Translation(); got != tt.senses {
+				t.Errorf("got senses %q, want %q", got, tt.senses)
+			}
+			if got := w.TranslationIn("ru"); got != tt.ru {
+				t.Errorf("got ru %q, want %q", got, tt.ru)
+			}
+			if got := w.TranslationIn("en"); got != tt.en {
+				t.Errorf("got en %q, want %q", got, tt.en)
+			}
+		})
+	}
+}
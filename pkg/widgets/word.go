@@ -0,0 +1,65 @@
+package widgets
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/egregors/karten/pkg/store"
+	"github.com/muesli/termenv"
+)
+
+var (
+	titleStyle = termenv.Style{}.Foreground(color("150")).Styled
+	labelStyle = termenv.Style{}.Foreground(color("241")).Styled
+)
+
+// WordWidget returns all the information about the Word, senses and examples in the first of languages it has
+func WordWidget(w *store.Word, langs ...string) string {
+	lines := []string{titleStyle(w.Origin)}
+	for i, s := range w.SensesIn(langs...) {
+		lines = append(lines, fmt.Sprintf("  %d. %s", i+1, s))
+		if s.Example != "" {
+			lines = append(lines, labelStyle("     "+s.Example))
+		}
+	}
+	for _, e := range w.Examples {
+		lines = append(lines, "  » "+e.Text)
+		if t := e.TranslationIn(langs...); t != "" {
+			lines = append(lines, labelStyle("    "+t))
+		}
+	}
+	lines = append(lines, "")
+
+	fields := []struct{ title, val string }{
+		{"score", fmt.Sprintf("%d/%d", w.Score, store.MaxScore)},
+		{"last seen", formatTime(w.LastSeenAt)},
+		{"added", formatTime(w.AddedAt)},
+		{"deck", w.Deck},
+		{"tags", strings.Join(w.Tags, ", ")},
+		{"example", w.Example},
+		{"mnemonic", w.Mnemonic},
+		{"notes", w.Notes},
+	}
+	if w.Suspended {
+		fields = append(fields, struct{ title, val string }{"suspended", "yes"})
+	}
+	for _, f := range fields {
+		if f.val != "" {
+			lines = append(lines, fmt.Sprintf("%s %s", labelStyle(fmt.Sprintf("%-10s", f.title+":")), f.val))
+		}
+	}
+
+	if w.HasMeta() {
+		lines = append(lines, "", w.Meta)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}